@@ -0,0 +1,52 @@
+package git
+
+import (
+	"github.com/blang/semver"
+)
+
+// Current is the Repository of the current working directory.
+var Current = Repository{}
+
+func Describe() (Description, error) {
+	return Current.Describe()
+}
+
+func Tag() (string, error) {
+	return Current.Tag()
+}
+
+func ExactTag() (string, error) {
+	return Current.ExactTag()
+}
+
+func Version() (semver.Version, error) {
+	return Current.Version()
+}
+
+func Commit() (string, error) {
+	return Current.Commit()
+}
+
+func ShortCommit() (string, error) {
+	return Current.ShortCommit()
+}
+
+func Dirty() (bool, error) {
+	return Current.Dirty()
+}
+
+func Branch() (string, error) {
+	return Current.Branch()
+}
+
+func CommitsSince(ref string) (int, error) {
+	return Current.CommitsSince(ref)
+}
+
+func CommitsSinceTag() (int, error) {
+	return Current.CommitsSinceTag()
+}
+
+func ChangedFiles(ref string) ([]string, error) {
+	return Current.ChangedFiles(ref)
+}
@@ -0,0 +1,212 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/blang/semver"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrNoTag = errors.New("no tag")
+
+	describePattern = regexp.MustCompile(`^(.+)-([0-9]+)-g([0-9a-f]+)(-dirty)?$`)
+)
+
+// Repository is a git work tree. All functions of this package which are
+// not bound to a Repository use the one of the current working directory.
+type Repository struct {
+	Dir string // Directory inside of the work tree; empty means the current working directory
+}
+
+// Description is the result of git describe.
+type Description struct {
+	Tag             string // Latest tag reachable from HEAD
+	CommitsSinceTag int    // Number of commits on top of Tag
+	ShortCommit     string // Abbreviated hash of HEAD
+	Dirty           bool   // True if the work tree contains uncommitted changes
+}
+
+// String returns the description in the format of git describe, e.g.
+// v1.2.3, v1.2.3-dirty or v1.2.3-4-gabcdef0.
+func (instance Description) String() string {
+	result := instance.Tag
+	if instance.CommitsSinceTag > 0 {
+		result += fmt.Sprintf("-%d-g%s", instance.CommitsSinceTag, instance.ShortCommit)
+	}
+	if instance.Dirty {
+		result += "-dirty"
+	}
+	return result
+}
+
+// Version returns the semantic version of Tag (a leading "v" is allowed). If
+// there are commits on top of the tag or the work tree is dirty this is
+// recorded as build metadata, e.g. v1.2.3-4-gabcdef0-dirty results in
+// 1.2.3+4.gabcdef0.dirty.
+func (instance Description) Version() (semver.Version, error) {
+	result, err := semver.ParseTolerant(instance.Tag)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("tag '%s' is not a semantic version: %v", instance.Tag, err)
+	}
+	if instance.CommitsSinceTag > 0 {
+		result.Build = append(result.Build, strconv.Itoa(instance.CommitsSinceTag), "g"+instance.ShortCommit)
+	}
+	if instance.Dirty {
+		result.Build = append(result.Build, "dirty")
+	}
+	return result, nil
+}
+
+// Describe describes HEAD by the latest reachable tag. If there is no tag
+// ErrNoTag is returned.
+func (instance Repository) Describe() (Description, error) {
+	out, err := instance.describe("--long", "--dirty")
+	if err != nil {
+		return Description{}, err
+	}
+	match := describePattern.FindStringSubmatch(out)
+	if match == nil {
+		return Description{}, fmt.Errorf("cannot parse output of 'git describe': %s", out)
+	}
+	commits, err := strconv.Atoi(match[2])
+	if err != nil {
+		return Description{}, fmt.Errorf("cannot parse output of 'git describe': %s", out)
+	}
+	return Description{
+		Tag:             match[1],
+		CommitsSinceTag: commits,
+		ShortCommit:     match[3],
+		Dirty:           match[4] != "",
+	}, nil
+}
+
+// Tag returns the latest tag reachable from HEAD. If there is no tag
+// ErrNoTag is returned.
+func (instance Repository) Tag() (string, error) {
+	return instance.describe("--abbrev=0")
+}
+
+// ExactTag returns the tag which points to HEAD or an empty string if there
+// is none.
+func (instance Repository) ExactTag() (string, error) {
+	result, err := instance.describe("--exact-match", "HEAD")
+	if err == ErrNoTag {
+		return "", nil
+	}
+	return result, err
+}
+
+// Version returns the semantic version of HEAD (see Description.Version).
+func (instance Repository) Version() (semver.Version, error) {
+	description, err := instance.Describe()
+	if err != nil {
+		return semver.Version{}, err
+	}
+	return description.Version()
+}
+
+// Commit returns the full hash of HEAD.
+func (instance Repository) Commit() (string, error) {
+	return instance.output("rev-parse", "HEAD")
+}
+
+// ShortCommit returns the abbreviated hash of HEAD.
+func (instance Repository) ShortCommit() (string, error) {
+	return instance.output("rev-parse", "--short", "HEAD")
+}
+
+// Dirty returns true if the work tree contains uncommitted changes or
+// untracked files which are not ignored.
+func (instance Repository) Dirty() (bool, error) {
+	out, err := instance.output("status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	return out != "", nil
+}
+
+// Branch returns the name of the current branch or an empty string if HEAD
+// is detached.
+func (instance Repository) Branch() (string, error) {
+	out, err := instance.output("rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	} else if out == "HEAD" {
+		return "", nil
+	}
+	return out, nil
+}
+
+// CommitsSince returns the number of commits which are reachable from HEAD
+// but not from ref.
+func (instance Repository) CommitsSince(ref string) (int, error) {
+	out, err := instance.output("rev-list", "--count", ref+"..HEAD")
+	if err != nil {
+		return 0, err
+	}
+	result, err := strconv.Atoi(out)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse output of 'git rev-list': %s", out)
+	}
+	return result, nil
+}
+
+// CommitsSinceTag returns the number of commits on top of the latest tag
+// reachable from HEAD. If there is no tag ErrNoTag is returned.
+func (instance Repository) CommitsSinceTag() (int, error) {
+	description, err := instance.Describe()
+	if err != nil {
+		return 0, err
+	}
+	return description.CommitsSinceTag, nil
+}
+
+// ChangedFiles returns the files which differ between ref and the work tree,
+// including committed and uncommitted changes of tracked files. The paths
+// are relative to the root of the work tree.
+func (instance Repository) ChangedFiles(ref string) ([]string, error) {
+	out, err := instance.output("diff", "--name-only", "-z", ref, "--")
+	if err != nil {
+		return nil, err
+	}
+	var result []string
+	for _, file := range strings.Split(out, "\x00") {
+		if file != "" {
+			result = append(result, file)
+		}
+	}
+	return result, nil
+}
+
+func (instance Repository) describe(args ...string) (string, error) {
+	out, err := instance.output(append([]string{"describe", "--tags"}, args...)...)
+	if err != nil && (strings.Contains(err.Error(), "No names found") ||
+		strings.Contains(err.Error(), "No tags can describe") ||
+		strings.Contains(err.Error(), "no tag exactly matches")) {
+		return "", ErrNoTag
+	}
+	return out, err
+}
+
+func (instance Repository) output(args ...string) (string, error) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	c := exec.Command("git", args...)
+	c.Dir = instance.Dir
+	// Messages like the ones describe detects ErrNoTag by are translated.
+	c.Env = append(os.Environ(), "LC_ALL=C")
+	c.Stdout = stdout
+	c.Stderr = stderr
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("cannot execute 'git %s': %v: %s", strings.Join(args, " "), err, msg)
+		}
+		return "", fmt.Errorf("cannot execute 'git %s': %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
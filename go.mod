@@ -5,6 +5,8 @@ go 1.14
 require (
 	github.com/blang/semver v3.5.1+incompatible
 	github.com/joho/godotenv v1.3.0
+	github.com/klauspost/compress v1.9.2
 	github.com/magefile/mage v1.9.0
 	github.com/mholt/archiver/v3 v3.3.0
+	golang.org/x/crypto v0.0.0-20200622213623-75b288015ac9
 )
@@ -22,7 +22,7 @@ func Method(method string) BeforeRequestPlugin {
 }
 
 func BearerAuth(token string) BeforeRequestPlugin {
-	return Header("Authorization", "Bearer: "+token)
+	return Header("Authorization", "Bearer "+token)
 }
 
 func BasicAuth(user, password string) BeforeRequestPlugin {
@@ -0,0 +1,348 @@
+package io
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"github.com/klauspost/compress/zstd"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvSourceDateEpoch contains the unix timestamp all timestamps inside of
+// archives are clamped to; see https://reproducible-builds.org/specs/source-date-epoch/
+const EnvSourceDateEpoch = "SOURCE_DATE_EPOCH"
+
+// ArchiveFormat is the format of an archive created by ArchiveWith.
+type ArchiveFormat string
+
+const (
+	ArchiveTarGz  ArchiveFormat = "tar.gz"
+	ArchiveTarZst ArchiveFormat = "tar.zst"
+	ArchiveZip    ArchiveFormat = "zip"
+)
+
+// ArchiveOptions controls how ArchiveWith creates archives.
+type ArchiveOptions struct {
+	Format   ArchiveFormat // Empty means it is derived from the extension of the target
+	Includes []string      // Glob patterns (see MatchGlob) of the files to archive, relative to the source directory; empty means all
+	Excludes []string      // Glob patterns of the files and directories to skip, relative to the source directory
+	Prefix   string        // Directory inside of the archive which contains all files; empty means the root
+}
+
+// Archive archives the content of the directory from into target. See
+// ArchiveWith.
+func Archive(target, from string) error {
+	return ArchiveWith(target, from, ArchiveOptions{})
+}
+
+// ArchiveWith archives the content of the directory from into target. The
+// archive is reproducible: Entries are sorted by name, owners are removed,
+// modes are normalized to 0755 (directories and executables) or 0644 and
+// timestamps are clamped to EnvSourceDateEpoch if set. Archiving the same
+// files twice results in byte-identical archives.
+func ArchiveWith(target, from string, options ArchiveOptions) error {
+	format, err := options.format(target)
+	if err != nil {
+		return err
+	}
+	if err := validateGlobs(options.Includes); err != nil {
+		return err
+	}
+	if err := validateGlobs(options.Excludes); err != nil {
+		return err
+	}
+	epoch, err := sourceDateEpoch()
+	if err != nil {
+		return err
+	}
+	entries, err := archiveEntriesOf(from, options, epoch)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("cannot create parent of '%s': %v", target, err)
+	}
+	return WriteAtomic(target, 0644, func(w io.Writer) error {
+		switch format {
+		case ArchiveZip:
+			return writeZip(w, entries)
+		case ArchiveTarZst:
+			return writeTarZst(w, entries)
+		default:
+			return writeTarGz(w, entries)
+		}
+	})
+}
+
+func (instance ArchiveOptions) format(target string) (ArchiveFormat, error) {
+	if instance.Format != "" {
+		switch instance.Format {
+		case ArchiveTarGz, ArchiveTarZst, ArchiveZip:
+			return instance.Format, nil
+		}
+		return "", fmt.Errorf("unsupported archive format '%s'; supported are: %s, %s and %s", instance.Format, ArchiveTarGz, ArchiveTarZst, ArchiveZip)
+	}
+	name := strings.ToLower(target)
+	switch {
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		return ArchiveTarGz, nil
+	case strings.HasSuffix(name, ".tar.zst"), strings.HasSuffix(name, ".tzst"):
+		return ArchiveTarZst, nil
+	case strings.HasSuffix(name, ".zip"):
+		return ArchiveZip, nil
+	}
+	return "", fmt.Errorf("cannot derive archive format of '%s'; use .tar.gz, .tar.zst or .zip", target)
+}
+
+type archiveEntry struct {
+	file    string // Path on the file system
+	name    string // Slash separated name inside of the archive; directories end with /
+	mode    os.FileMode
+	modTime time.Time
+	link    string // Target of symlinks
+}
+
+func archiveEntriesOf(from string, options ArchiveOptions, epoch *time.Time) ([]archiveEntry, error) {
+	prefix := strings.Trim(path.Clean("/"+filepath.ToSlash(options.Prefix)), "/")
+	byName := map[string]archiveEntry{}
+	add := func(file, rel string, fi os.FileInfo) error {
+		name := path.Join(prefix, rel)
+		if name == "." || name == "" {
+			return nil
+		}
+		entry := archiveEntry{file: file, name: name, modTime: clampTime(fi.ModTime(), epoch)}
+		switch {
+		case fi.IsDir():
+			entry.name += "/"
+			entry.mode = os.ModeDir | 0755
+		case fi.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(file)
+			if err != nil {
+				return fmt.Errorf("cannot read link '%s': %v", file, err)
+			}
+			entry.mode, entry.link = os.ModeSymlink|0777, filepath.ToSlash(link)
+		case fi.Mode().IsRegular() && fi.Mode()&0111 != 0:
+			entry.mode = 0755
+		case fi.Mode().IsRegular():
+			entry.mode = 0644
+		default:
+			return fmt.Errorf("cannot archive '%s': unsupported file type %v", file, fi.Mode()&os.ModeType)
+		}
+		byName[entry.name] = entry
+		return nil
+	}
+	// Directories containing included files are added, even if they are not
+	// included themselves.
+	addParents := func(rel string) error {
+		var parents []string
+		for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+			parents = append(parents, dir)
+		}
+		for i := len(parents) - 1; i >= 0; i-- {
+			if _, ok := byName[path.Join(prefix, parents[i])+"/"]; ok {
+				continue
+			}
+			file := filepath.Join(from, filepath.FromSlash(parents[i]))
+			fi, err := os.Stat(file)
+			if err != nil {
+				return fmt.Errorf("cannot archive '%s': %v", file, err)
+			}
+			if err := add(file, parents[i], fi); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := filepath.Walk(from, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("cannot archive '%s': %v", file, err)
+		}
+		rel, err := filepath.Rel(from, file)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		name := filepath.ToSlash(rel)
+		if excluded, err := MatchAnyGlob(options.Excludes, name); err != nil {
+			return err
+		} else if excluded && fi.IsDir() {
+			return filepath.SkipDir
+		} else if excluded {
+			return nil
+		}
+		if len(options.Includes) > 0 {
+			if fi.IsDir() {
+				return nil
+			}
+			if included, err := MatchAnyGlob(options.Includes, name); err != nil || !included {
+				return err
+			}
+			if err := addParents(name); err != nil {
+				return err
+			}
+		}
+		return add(file, name, fi)
+	}); err != nil {
+		return nil, err
+	}
+
+	if prefix != "" && len(byName) > 0 {
+		// The prefix directories do not exist on the file system; they get
+		// the time of the newest entry.
+		var latest time.Time
+		for _, entry := range byName {
+			if entry.modTime.After(latest) {
+				latest = entry.modTime
+			}
+		}
+		for dir := prefix; dir != "."; dir = path.Dir(dir) {
+			byName[dir+"/"] = archiveEntry{name: dir + "/", mode: os.ModeDir | 0755, modTime: latest}
+		}
+	}
+
+	result := make([]archiveEntry, 0, len(byName))
+	for _, entry := range byName {
+		result = append(result, entry)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].name < result[j].name
+	})
+	return result, nil
+}
+
+func writeTarGz(w io.Writer, entries []archiveEntry) error {
+	// A zero header (no name, no modification time) keeps the output
+	// reproducible.
+	gw, err := gzip.NewWriterLevel(w, gzip.BestCompression)
+	if err != nil {
+		return err
+	}
+	if err := writeTar(gw, entries); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func writeTarZst(w io.Writer, entries []archiveEntry) error {
+	// Concurrent encoding could change the output between runs.
+	zw, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return err
+	}
+	if err := writeTar(zw, entries); err != nil {
+		zw.Close()
+		return err
+	}
+	return zw.Close()
+}
+
+func writeTar(w io.Writer, entries []archiveEntry) error {
+	tw := tar.NewWriter(w)
+	for _, entry := range entries {
+		header := &tar.Header{
+			Name:    entry.name,
+			Mode:    int64(entry.mode.Perm()),
+			ModTime: entry.modTime,
+			Format:  tar.FormatPAX,
+		}
+		switch {
+		case entry.mode.IsDir():
+			header.Typeflag = tar.TypeDir
+		case entry.mode&os.ModeSymlink != 0:
+			header.Typeflag, header.Linkname = tar.TypeSymlink, entry.link
+		default:
+			header.Typeflag = tar.TypeReg
+			fi, err := os.Stat(entry.file)
+			if err != nil {
+				return fmt.Errorf("cannot archive '%s': %v", entry.file, err)
+			}
+			header.Size = fi.Size()
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("cannot archive '%s': %v", entry.file, err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if err := copyFileTo(tw, entry.file); err != nil {
+				return err
+			}
+		}
+	}
+	return tw.Close()
+}
+
+func writeZip(w io.Writer, entries []archiveEntry) error {
+	zw := zip.NewWriter(w)
+	for _, entry := range entries {
+		header := &zip.FileHeader{
+			Name:     entry.name,
+			Method:   zip.Deflate,
+			Modified: entry.modTime,
+		}
+		header.SetMode(entry.mode)
+		if entry.mode.IsDir() {
+			header.Method = zip.Store
+		}
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			return fmt.Errorf("cannot archive '%s': %v", entry.file, err)
+		}
+		switch {
+		case entry.mode.IsDir():
+		case entry.mode&os.ModeSymlink != 0:
+			if _, err := io.WriteString(fw, entry.link); err != nil {
+				return err
+			}
+		default:
+			if err := copyFileTo(fw, entry.file); err != nil {
+				return err
+			}
+		}
+	}
+	return zw.Close()
+}
+
+func copyFileTo(w io.Writer, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("cannot archive '%s': %v", file, err)
+	}
+	defer CloseQuietly(f)
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("cannot archive '%s': %v", file, err)
+	}
+	return nil
+}
+
+// sourceDateEpoch returns the time of EnvSourceDateEpoch or nil if not set.
+func sourceDateEpoch() (*time.Time, error) {
+	plain := strings.TrimSpace(os.Getenv(EnvSourceDateEpoch))
+	if plain == "" {
+		return nil, nil
+	}
+	seconds, err := strconv.ParseInt(plain, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("illegal value of %s: %s", EnvSourceDateEpoch, plain)
+	}
+	result := time.Unix(seconds, 0).UTC()
+	return &result, nil
+}
+
+// clampTime returns t, or epoch if t is after it, in UTC and in seconds.
+// Sub-second precision is removed because not all formats could represent it.
+func clampTime(t time.Time, epoch *time.Time) time.Time {
+	if epoch != nil && t.After(*epoch) {
+		t = *epoch
+	}
+	return t.UTC().Truncate(time.Second)
+}
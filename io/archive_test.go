@@ -0,0 +1,88 @@
+package io
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestArchiveWithIsReproducible(t *testing.T) {
+	if err := os.Setenv(EnvSourceDateEpoch, "1500000000"); err != nil {
+		t.Fatal(err)
+	}
+	//noinspection GoUnhandledErrorResult
+	defer os.Unsetenv(EnvSourceDateEpoch)
+
+	base, err := ioutil.TempDir("", "mageplus-archive-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(base)
+
+	files := []struct {
+		name    string
+		content string
+		mode    os.FileMode
+	}{
+		{"a.txt", "a", 0644},
+		{"bin/tool", "#!/bin/sh\n", 0755},
+		{"sub/dir/b.txt", "b", 0644},
+	}
+	// The second tree is created in reverse order at another time with other
+	// modes, which all must not influence the archive.
+	createTree := func(dir string, reverse bool, readOnly bool) {
+		for i := range files {
+			f := files[i]
+			if reverse {
+				f = files[len(files)-1-i]
+			}
+			file := filepath.Join(dir, filepath.FromSlash(f.name))
+			if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+				t.Fatal(err)
+			}
+			mode := f.mode
+			if readOnly {
+				mode &^= 0222
+			}
+			if err := ioutil.WriteFile(file, []byte(f.content), mode); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chmod(file, mode); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	first, second := filepath.Join(base, "first"), filepath.Join(base, "second")
+	createTree(first, false, false)
+	createTree(second, true, true)
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(filepath.Join(second, "a.txt"), later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, format := range []ArchiveFormat{ArchiveTarGz, ArchiveTarZst, ArchiveZip} {
+		t.Run(string(format), func(t *testing.T) {
+			var archives [][]byte
+			for _, from := range []string{first, first, second} {
+				target := filepath.Join(base, "archive."+string(format))
+				if err := ArchiveWith(target, from, ArchiveOptions{Prefix: "app"}); err != nil {
+					t.Fatal(err)
+				}
+				b, err := ioutil.ReadFile(target)
+				if err != nil {
+					t.Fatal(err)
+				}
+				archives = append(archives, b)
+			}
+			for i := 1; i < len(archives); i++ {
+				if !bytes.Equal(archives[0], archives[i]) {
+					t.Errorf("archive %d differs from the first one", i)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+package io
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ChecksumAlgorithm is the hash algorithm of Checksums.
+type ChecksumAlgorithm string
+
+const (
+	Sha256 ChecksumAlgorithm = "sha256"
+	Sha512 ChecksumAlgorithm = "sha512"
+)
+
+var (
+	ChecksumAlgorithms = []ChecksumAlgorithm{Sha256, Sha512}
+
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+// ParseChecksumAlgorithm returns the ChecksumAlgorithm of the given name.
+func ParseChecksumAlgorithm(plain string) (ChecksumAlgorithm, error) {
+	for _, candidate := range ChecksumAlgorithms {
+		if strings.EqualFold(string(candidate), plain) {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported checksum algorithm '%s'; supported are: %s and %s", plain, Sha256, Sha512)
+}
+
+// ChecksumAlgorithmOf returns the ChecksumAlgorithm which produces checksums
+// like the given hex encoded one.
+func ChecksumAlgorithmOf(checksum string) (ChecksumAlgorithm, error) {
+	switch len(checksum) {
+	case sha256.Size * 2:
+		return Sha256, nil
+	case sha512.Size * 2:
+		return Sha512, nil
+	}
+	return "", fmt.Errorf("unsupported checksum '%s'", checksum)
+}
+
+// FileName returns the conventional name of files containing checksums of
+// this algorithm, e.g. SHA256SUMS.
+func (instance ChecksumAlgorithm) FileName() string {
+	return strings.ToUpper(string(instance)) + "SUMS"
+}
+
+func (instance ChecksumAlgorithm) New() hash.Hash {
+	if instance == Sha512 {
+		return sha512.New()
+	}
+	return sha256.New()
+}
+
+// Sum returns the hex encoded checksum of the content of r.
+func (instance ChecksumAlgorithm) Sum(r io.Reader) (string, error) {
+	h := instance.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// SumFile returns the hex encoded checksum of the given file.
+func (instance ChecksumAlgorithm) SumFile(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", fmt.Errorf("cannot calculate checksum of '%s': %v", file, err)
+	}
+	defer CloseQuietly(f)
+	result, err := instance.Sum(f)
+	if err != nil {
+		return "", fmt.Errorf("cannot calculate checksum of '%s': %v", file, err)
+	}
+	return result, nil
+}
+
+// VerifyChecksum verifies that the content of the given file matches the
+// expected hex encoded checksum. The algorithm is derived from the length of
+// the checksum. A mismatch results in an error wrapping ErrChecksumMismatch.
+func VerifyChecksum(file, expected string) error {
+	expected = strings.ToLower(strings.TrimSpace(expected))
+	algorithm, err := ChecksumAlgorithmOf(expected)
+	if err != nil {
+		return err
+	}
+	actual, err := algorithm.SumFile(file)
+	if err != nil {
+		return err
+	}
+	if actual != expected {
+		return &checksumMismatchError{file: file, expected: expected, actual: actual}
+	}
+	return nil
+}
+
+type checksumMismatchError struct {
+	file     string
+	expected string
+	actual   string
+}
+
+func (instance *checksumMismatchError) Error() string {
+	return fmt.Sprintf("%v of '%s': expected %s but got %s", ErrChecksumMismatch, instance.file, instance.expected, instance.actual)
+}
+
+func (instance *checksumMismatchError) Unwrap() error {
+	return ErrChecksumMismatch
+}
+
+// vcsDirs are the names of the directories of version control systems, which
+// GenerateChecksums never enters.
+var vcsDirs = map[string]bool{
+	".git": true,
+	".hg":  true,
+	".svn": true,
+}
+
+// Checksums are hex encoded checksums by slash separated file name.
+type Checksums map[string]string
+
+// GenerateChecksums calculates the checksums of all files inside of dir
+// which match at least one of the given glob patterns (see MatchGlob). If
+// no pattern is given all files directly inside of dir are used and its
+// subdirectories are not visited at all. Files named like
+// ChecksumAlgorithm.FileName and directories of version control systems are
+// always skipped.
+func GenerateChecksums(algorithm ChecksumAlgorithm, dir string, patterns ...string) (Checksums, error) {
+	topLevelOnly := len(patterns) == 0
+	if topLevelOnly {
+		patterns = []string{"*"}
+	}
+	if err := validateGlobs(patterns); err != nil {
+		return nil, err
+	}
+	result := Checksums{}
+	if err := filepath.Walk(dir, func(file string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("cannot calculate checksums of '%s': %v", dir, err)
+		}
+		if fi.IsDir() && file != dir && (topLevelOnly || vcsDirs[fi.Name()]) {
+			return filepath.SkipDir
+		}
+		if !fi.Mode().IsRegular() || isChecksumsFile(fi.Name()) {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if ok, err := MatchAnyGlob(patterns, name); err != nil || !ok {
+			return err
+		}
+		if result[name], err = algorithm.SumFile(file); err != nil {
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ParseChecksums parses the format of sha256sum and sha512sum of GNU
+// coreutils: one "<checksum>  <name>" (or "<checksum> *<name>" for binary
+// mode) per line.
+func ParseChecksums(r io.Reader) (Checksums, error) {
+	result := Checksums{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		plain := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(plain) == "" {
+			continue
+		}
+		escaped := strings.HasPrefix(plain, "\\")
+		if escaped {
+			plain = plain[1:]
+		}
+		i := strings.IndexByte(plain, ' ')
+		if i <= 0 || len(plain) < i+2 || (plain[i+1] != ' ' && plain[i+1] != '*') {
+			return nil, fmt.Errorf("illegal checksum line %d: %s", line, plain)
+		}
+		checksum, name := strings.ToLower(plain[:i]), plain[i+2:]
+		if _, err := hex.DecodeString(checksum); err != nil {
+			return nil, fmt.Errorf("illegal checksum line %d: %s", line, plain)
+		}
+		if escaped {
+			name = unescapeChecksumName(name)
+		}
+		result[name] = checksum
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReadChecksums reads the given file; see ParseChecksums.
+func ReadChecksums(file string) (Checksums, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read checksums '%s': %v", file, err)
+	}
+	defer CloseQuietly(f)
+	result, err := ParseChecksums(f)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read checksums '%s': %v", file, err)
+	}
+	return result, nil
+}
+
+// Names returns the sorted names of all files.
+func (instance Checksums) Names() []string {
+	result := make([]string, 0, len(instance))
+	for name := range instance {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Bytes returns the checksums, sorted by name, in the format accepted by
+// ParseChecksums.
+func (instance Checksums) Bytes() []byte {
+	buf := new(bytes.Buffer)
+	for _, name := range instance.Names() {
+		checksum := instance[name]
+		if strings.ContainsAny(name, "\\\n") {
+			buf.WriteByte('\\')
+			name = escapeChecksumName(name)
+		}
+		_, _ = fmt.Fprintf(buf, "%s  %s\n", checksum, name)
+	}
+	return buf.Bytes()
+}
+
+// Write writes the checksums (atomically) to the given file.
+func (instance Checksums) Write(file string) error {
+	if err := WriteFileAtomic(file, instance.Bytes(), 0644); err != nil {
+		return fmt.Errorf("cannot write checksums: %v", err)
+	}
+	return nil
+}
+
+// Verify verifies the files inside of dir against the checksums. All missing
+// and mismatching files are reported together.
+func (instance Checksums) Verify(dir string) error {
+	var problems []error
+	for _, name := range instance.Names() {
+		if err := VerifyChecksum(filepath.Join(dir, filepath.FromSlash(name)), instance[name]); err != nil {
+			problems = append(problems, err)
+		}
+	}
+	switch len(problems) {
+	case 0:
+		return nil
+	case 1:
+		return problems[0]
+	}
+	lines := make([]string, len(problems))
+	for i, problem := range problems {
+		lines[i] = problem.Error()
+	}
+	return fmt.Errorf("%d files do not match their checksums:\n\t%s", len(problems), strings.Join(lines, "\n\t"))
+}
+
+// VerifyChecksumsFile verifies the files next to the given checksums file
+// (e.g. SHA256SUMS) against it. See Checksums.Verify.
+func VerifyChecksumsFile(file string) error {
+	checksums, err := ReadChecksums(file)
+	if err != nil {
+		return err
+	}
+	return checksums.Verify(filepath.Dir(file))
+}
+
+func isChecksumsFile(name string) bool {
+	for _, algorithm := range ChecksumAlgorithms {
+		if name == algorithm.FileName() {
+			return true
+		}
+	}
+	return false
+}
+
+func escapeChecksumName(name string) string {
+	return strings.NewReplacer("\\", "\\\\", "\n", "\\n").Replace(name)
+}
+
+func unescapeChecksumName(name string) string {
+	return strings.NewReplacer("\\\\", "\\", "\\n", "\n").Replace(name)
+}
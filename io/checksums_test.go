@@ -0,0 +1,105 @@
+package io
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestChecksumsBytesAndParseChecksums(t *testing.T) {
+	checksums := Checksums{
+		"a.txt":         strings.Repeat("0", 64),
+		"dir/b c.txt":   strings.Repeat("1", 64),
+		"back\\slash":   strings.Repeat("2", 64),
+		"new\nline":     strings.Repeat("3", 64),
+		"both\\n\nline": strings.Repeat("4", 64),
+	}
+	actual, err := ParseChecksums(bytes.NewReader(checksums.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(actual, checksums) {
+		t.Errorf("expected %q but got %q", checksums, actual)
+	}
+}
+
+func TestParseChecksumsOfCoreutils(t *testing.T) {
+	plain := strings.Repeat("A", 64) + " *binary.exe\r\n" +
+		"\n" +
+		strings.Repeat("b", 64) + "  text.txt\n"
+	actual, err := ParseChecksums(strings.NewReader(plain))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Checksums{
+		"binary.exe": strings.Repeat("a", 64),
+		"text.txt":   strings.Repeat("b", 64),
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %q but got %q", expected, actual)
+	}
+
+	for _, illegal := range []string{"abc", "xyz  file", strings.Repeat("0", 64) + " -file"} {
+		if _, err := ParseChecksums(strings.NewReader(illegal)); err == nil {
+			t.Errorf("expected an error for %q", illegal)
+		}
+	}
+}
+
+func TestGenerateAndVerifyChecksums(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mageplus-checksums-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	//noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(dir)
+	for name, content := range map[string]string{
+		"a.txt":       "a",
+		"sub/b.txt":   "b",
+		".git/config": "c",
+	} {
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(file, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	topLevel, err := GenerateChecksums(Sha256, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a.txt"}; !reflect.DeepEqual(topLevel.Names(), expected) {
+		t.Errorf("expected %v but got %v", expected, topLevel.Names())
+	}
+
+	all, err := GenerateChecksums(Sha256, dir, "**")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a.txt", "sub/b.txt"}; !reflect.DeepEqual(all.Names(), expected) {
+		t.Errorf("expected %v but got %v", expected, all.Names())
+	}
+
+	file := filepath.Join(dir, Sha256.FileName())
+	if err := all.Write(file); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyChecksumsFile(file); err != nil {
+		t.Errorf("expected no error but got %v", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "sub", "b.txt"), []byte("changed"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyChecksumsFile(file); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("expected %v but got %v", ErrChecksumMismatch, err)
+	}
+}
@@ -0,0 +1,326 @@
+package io
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CopyOptions controls how CopyWith and MoveWith transfer files.
+type CopyOptions struct {
+	Includes []string    // Glob patterns (see MatchGlob) of the files to transfer, relative to the source directory; empty means all
+	Excludes []string    // Glob patterns of the files and directories to skip, relative to the source directory
+	DryRun   bool        // If true nothing is changed but all actions are reported
+	Log      *log.Logger // Receives the actions; if nil only dry runs are reported (to stderr)
+}
+
+// Copy copies the file or directory tree from to to. See CopyWith.
+func Copy(from, to string) error {
+	return CopyWith(from, to, CopyOptions{})
+}
+
+// CopyWith copies the file or directory tree from to to. Modes, symlinks and
+// modification times are preserved; existing files are overwritten. If from
+// is a directory, Includes and Excludes select which of its files are copied.
+// to must neither be from itself nor lie beneath it.
+func CopyWith(from, to string, options CopyOptions) error {
+	t, err := newTransfer(from, to, options)
+	if err != nil {
+		return err
+	}
+	return t.run(false)
+}
+
+// Move moves the file or directory tree from to to. See MoveWith.
+func Move(from, to string) error {
+	return MoveWith(from, to, CopyOptions{})
+}
+
+// MoveWith is like CopyWith but removes the transferred files (and the
+// directories which become empty) from the source. Files are renamed if
+// possible; otherwise (e.g. across devices) they are copied and removed
+// afterwards.
+func MoveWith(from, to string, options CopyOptions) error {
+	t, err := newTransfer(from, to, options)
+	if err != nil {
+		return err
+	}
+	if len(options.Includes) == 0 && len(options.Excludes) == 0 && !options.DryRun {
+		if err := os.Rename(from, to); err == nil {
+			t.report("move", from, to)
+			return nil
+		}
+		// Continue transferring file by file...
+	}
+	return t.run(true)
+}
+
+type transfer struct {
+	from    string
+	to      string
+	options CopyOptions
+	log     *log.Logger
+
+	ensuredDirs map[string]os.FileInfo // Source directories by relative path, stated before moves modify them
+	createdDirs []string               // Relative paths of the directories created by this transfer, parents first
+	visitedDirs []string               // Relative paths of the source directories, parents first
+}
+
+func newTransfer(from, to string, options CopyOptions) (*transfer, error) {
+	if err := validateGlobs(options.Includes); err != nil {
+		return nil, err
+	}
+	if err := validateGlobs(options.Excludes); err != nil {
+		return nil, err
+	}
+	if inside, err := isInside(to, from); err != nil {
+		return nil, err
+	} else if inside {
+		return nil, fmt.Errorf("cannot transfer '%s' into itself ('%s')", from, to)
+	}
+	l := options.Log
+	if l == nil && options.DryRun {
+		l = log.New(os.Stderr, "", 0)
+	} else if l == nil {
+		l = log.New(ioutil.Discard, "", 0)
+	}
+	return &transfer{
+		from:    filepath.Clean(from),
+		to:      filepath.Clean(to),
+		options: options,
+		log:     l,
+
+		ensuredDirs: map[string]os.FileInfo{},
+	}, nil
+}
+
+// isInside reports whether path is equal to or lies beneath dir.
+func isInside(path, dir string) (bool, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return false, fmt.Errorf("cannot resolve '%s': %v", path, err)
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false, fmt.Errorf("cannot resolve '%s': %v", dir, err)
+	}
+	if absPath == absDir {
+		return true, nil
+	}
+	return strings.HasPrefix(absPath, strings.TrimSuffix(absDir, string(filepath.Separator))+string(filepath.Separator)), nil
+}
+
+func (instance *transfer) run(move bool) error {
+	fi, err := os.Lstat(instance.from)
+	if err != nil {
+		return fmt.Errorf("cannot transfer '%s': %v", instance.from, err)
+	}
+	if !fi.IsDir() {
+		if !instance.options.DryRun {
+			if err := os.MkdirAll(filepath.Dir(instance.to), 0755); err != nil {
+				return fmt.Errorf("cannot create parent of '%s': %v", instance.to, err)
+			}
+		}
+		return instance.file(instance.from, instance.to, fi, move)
+	}
+
+	if err := filepath.Walk(instance.from, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return fmt.Errorf("cannot transfer '%s': %v", path, err)
+		}
+		rel, err := filepath.Rel(instance.from, path)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if rel != "." {
+			if excluded, err := MatchAnyGlob(instance.options.Excludes, name); err != nil {
+				return err
+			} else if excluded && fi.IsDir() {
+				return filepath.SkipDir
+			} else if excluded {
+				return nil
+			}
+		}
+		if fi.IsDir() {
+			instance.visitedDirs = append(instance.visitedDirs, rel)
+			if len(instance.options.Includes) > 0 {
+				// Only directories which contain included files are created.
+				return nil
+			}
+			return instance.ensureDir(rel)
+		}
+		if len(instance.options.Includes) > 0 {
+			if included, err := MatchAnyGlob(instance.options.Includes, name); err != nil || !included {
+				return err
+			}
+		}
+		if err := instance.ensureDir(filepath.Dir(rel)); err != nil {
+			return err
+		}
+		return instance.file(path, filepath.Join(instance.to, rel), fi, move)
+	}); err != nil {
+		return err
+	}
+	return instance.finish(move)
+}
+
+func (instance *transfer) ensureDir(rel string) error {
+	if _, ok := instance.ensuredDirs[rel]; ok {
+		return nil
+	}
+	if rel != "." {
+		if err := instance.ensureDir(filepath.Dir(rel)); err != nil {
+			return err
+		}
+	}
+	source, target := filepath.Join(instance.from, rel), filepath.Join(instance.to, rel)
+	fi, err := os.Stat(source)
+	if err != nil {
+		return fmt.Errorf("cannot transfer '%s': %v", source, err)
+	}
+	instance.ensuredDirs[rel] = fi
+	if ok, err := DirExists(target); err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+	instance.log.Printf("mkdir %s", target)
+	if instance.options.DryRun {
+		return nil
+	}
+	if rel == "." {
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("cannot create parent of '%s': %v", target, err)
+		}
+	}
+	// The final mode is applied by finish, after the content was written.
+	if err := os.Mkdir(target, 0755); err != nil {
+		return fmt.Errorf("cannot create directory '%s': %v", target, err)
+	}
+	// Existing directories (like the target itself) keep their mode and times.
+	instance.createdDirs = append(instance.createdDirs, rel)
+	return nil
+}
+
+func (instance *transfer) file(source, target string, fi os.FileInfo, move bool) error {
+	verb := "copy"
+	if move {
+		verb = "move"
+	}
+	instance.report(verb, source, target)
+	if instance.options.DryRun {
+		return nil
+	}
+
+	if move {
+		if err := os.Rename(source, target); err == nil {
+			return nil
+		}
+		// Continue copying it...
+	}
+
+	switch {
+	case fi.Mode()&os.ModeSymlink != 0:
+		if err := copySymlink(source, target); err != nil {
+			return err
+		}
+	case fi.Mode().IsRegular():
+		if err := copyFile(source, target, fi); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("cannot transfer '%s': unsupported file type %v", source, fi.Mode()&os.ModeType)
+	}
+
+	if move {
+		if err := os.Remove(source); err != nil {
+			return fmt.Errorf("cannot remove '%s': %v", source, err)
+		}
+	}
+	return nil
+}
+
+// finish applies the modes and modification times of the source directories
+// to the created directories and removes emptied source directories of
+// moves. Children are handled before their parents.
+func (instance *transfer) finish(move bool) error {
+	if instance.options.DryRun {
+		return nil
+	}
+	for i := len(instance.createdDirs) - 1; i >= 0; i-- {
+		rel := instance.createdDirs[i]
+		fi, target := instance.ensuredDirs[rel], filepath.Join(instance.to, rel)
+		if err := os.Chmod(target, fi.Mode().Perm()); err != nil {
+			return fmt.Errorf("cannot change mode of '%s': %v", target, err)
+		}
+		if err := os.Chtimes(target, fi.ModTime(), fi.ModTime()); err != nil {
+			return fmt.Errorf("cannot change times of '%s': %v", target, err)
+		}
+	}
+	if move {
+		for i := len(instance.visitedDirs) - 1; i >= 0; i-- {
+			// Fails for directories which still contain excluded files.
+			_ = os.Remove(filepath.Join(instance.from, instance.visitedDirs[i]))
+		}
+	}
+	return nil
+}
+
+func (instance *transfer) report(verb, source, target string) {
+	instance.log.Printf("%s %s -> %s", verb, source, target)
+}
+
+func copyFile(source, target string, fi os.FileInfo) error {
+	f, err := os.Open(source)
+	if err != nil {
+		return fmt.Errorf("cannot open '%s': %v", source, err)
+	}
+	defer CloseQuietly(f)
+	if err := removeIfSymlink(target); err != nil {
+		return err
+	}
+	if err := WriteAtomic(target, fi.Mode().Perm(), func(w io.Writer) error {
+		_, err := io.Copy(w, f)
+		return err
+	}); err != nil {
+		return err
+	}
+	// WriteAtomic preserves the mode of existing targets.
+	if err := os.Chmod(target, fi.Mode().Perm()); err != nil {
+		return fmt.Errorf("cannot change mode of '%s': %v", target, err)
+	}
+	if err := os.Chtimes(target, fi.ModTime(), fi.ModTime()); err != nil {
+		return fmt.Errorf("cannot change times of '%s': %v", target, err)
+	}
+	return nil
+}
+
+func copySymlink(source, target string) error {
+	link, err := os.Readlink(source)
+	if err != nil {
+		return fmt.Errorf("cannot read link '%s': %v", source, err)
+	}
+	if _, err := os.Lstat(target); err == nil {
+		if err := os.Remove(target); err != nil {
+			return fmt.Errorf("cannot replace '%s': %v", target, err)
+		}
+	}
+	if err := os.Symlink(link, target); err != nil {
+		return fmt.Errorf("cannot create link '%s': %v", target, err)
+	}
+	return nil
+}
+
+func removeIfSymlink(file string) error {
+	if fi, err := os.Lstat(file); err == nil && fi.Mode()&os.ModeSymlink != 0 {
+		if err := os.Remove(file); err != nil {
+			return fmt.Errorf("cannot replace '%s': %v", file, err)
+		}
+	}
+	return nil
+}
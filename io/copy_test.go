@@ -0,0 +1,154 @@
+package io
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCopyKeepsModeOfExistingTarget(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("modes of directories are not supported on Windows")
+	}
+	base := tempDir(t)
+	//noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(base)
+
+	from, to := filepath.Join(base, "from"), filepath.Join(base, "to")
+	writeTestFile(t, filepath.Join(from, "sub", "a.txt"), "a", 0600)
+	modTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, dir := range []string{filepath.Join(from, "sub"), from} {
+		if err := os.Chmod(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(dir, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(to, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(to, 0777|os.ModeSticky); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Copy(from, to); err != nil {
+		t.Fatal(err)
+	}
+
+	if fi, err := os.Stat(to); err != nil {
+		t.Fatal(err)
+	} else if mode := fi.Mode() & (os.ModePerm | os.ModeSticky); mode != 0777|os.ModeSticky {
+		t.Errorf("expected mode of existing target to be kept but got %v", mode)
+	}
+	if fi, err := os.Stat(filepath.Join(to, "sub")); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode().Perm() != 0700 || !fi.ModTime().Equal(modTime) {
+		t.Errorf("expected mode and time of source of created directory but got %v and %v", fi.Mode().Perm(), fi.ModTime())
+	}
+	if fi, err := os.Stat(filepath.Join(to, "sub", "a.txt")); err != nil {
+		t.Fatal(err)
+	} else if fi.Mode().Perm() != 0600 {
+		t.Errorf("expected mode 0600 of copied file but got %v", fi.Mode().Perm())
+	}
+}
+
+func TestCopyWithFilters(t *testing.T) {
+	base := tempDir(t)
+	//noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(base)
+
+	from, to := filepath.Join(base, "from"), filepath.Join(base, "to")
+	writeTestFile(t, filepath.Join(from, "a.go"), "a", 0644)
+	writeTestFile(t, filepath.Join(from, "a_test.go"), "test", 0644)
+	writeTestFile(t, filepath.Join(from, "sub", "b.go"), "b", 0644)
+	writeTestFile(t, filepath.Join(from, "sub", "b.txt"), "b", 0644)
+
+	if err := CopyWith(from, to, CopyOptions{Includes: []string{"**/*.go"}, Excludes: []string{"*_test.go"}}); err != nil {
+		t.Fatal(err)
+	}
+	assertFiles(t, to, "a.go", "sub/b.go")
+	assertFiles(t, from, "a.go", "a_test.go", "sub/b.go", "sub/b.txt")
+}
+
+func TestMove(t *testing.T) {
+	base := tempDir(t)
+	//noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(base)
+
+	from, to := filepath.Join(base, "from"), filepath.Join(base, "to")
+	writeTestFile(t, filepath.Join(from, "a.txt"), "a", 0644)
+	writeTestFile(t, filepath.Join(from, "sub", "b.txt"), "b", 0644)
+
+	if err := MoveWith(from, to, CopyOptions{Excludes: []string{"a.txt"}}); err != nil {
+		t.Fatal(err)
+	}
+	assertFiles(t, to, "sub/b.txt")
+	assertFiles(t, from, "a.txt")
+	if ok, err := Exists(filepath.Join(from, "sub")); err != nil || ok {
+		t.Errorf("expected emptied directory to be removed (err: %v)", err)
+	}
+}
+
+func TestCopyRejectsTargetInsideOfSource(t *testing.T) {
+	base := tempDir(t)
+	//noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(base)
+	writeTestFile(t, filepath.Join(base, "a.txt"), "a", 0644)
+
+	for _, to := range []string{base, filepath.Join(base, "sub", "copy")} {
+		if err := Copy(base, to); err == nil {
+			t.Errorf("expected copying %s into %s to fail", base, to)
+		}
+	}
+}
+
+func tempDir(t *testing.T) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "mageplus-copy-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func writeTestFile(t *testing.T, file, content string, perm os.FileMode) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(file, []byte(content), perm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(file, perm); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// assertFiles fails if dir does not contain exactly the given files (slash
+// separated, sorted).
+func assertFiles(t *testing.T, dir string, expected ...string) {
+	t.Helper()
+	var actual []string
+	if err := filepath.Walk(dir, func(file string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, file)
+		actual = append(actual, filepath.ToSlash(rel))
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("expected %v inside of %s but got %v", expected, dir, actual)
+	}
+	for i := range actual {
+		if actual[i] != expected[i] {
+			t.Fatalf("expected %v inside of %s but got %v", expected, dir, actual)
+		}
+	}
+}
@@ -1,11 +1,39 @@
 package io
 
 import (
+	"bytes"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
+	"time"
 )
 
+// Touch creates the given file with perm if it does not exist, otherwise it
+// only updates its access and modification time. The content is never
+// changed; see TouchTruncate.
+//
+// Existing files do not need to be writable, because they are not opened.
 func Touch(filename string, perm os.FileMode) error {
+	now := time.Now()
+	if err := os.Chtimes(filename, now, now); err == nil {
+		return nil
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("cannot touch '%s': %v", filename, err)
+	}
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE, perm)
+	if err != nil {
+		return fmt.Errorf("cannot touch '%s': %v", filename, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("cannot touch '%s': %v", filename, err)
+	}
+	return nil
+}
+
+// TouchTruncate is like Touch but removes the content of existing files.
+func TouchTruncate(filename string, perm os.FileMode) error {
 	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
 	if err != nil {
 		return fmt.Errorf("cannot touch '%s': %v", filename, err)
@@ -13,6 +41,60 @@ func Touch(filename string, perm os.FileMode) error {
 	return f.Close()
 }
 
+// WriteFileAtomic writes data to the given file. See WriteAtomic.
+func WriteFileAtomic(filename string, data []byte, perm os.FileMode) error {
+	return WriteAtomic(filename, perm, func(w io.Writer) error {
+		_, err := io.Copy(w, bytes.NewReader(data))
+		return err
+	})
+}
+
+// WriteAtomic writes the content produced by write to a temporary file in
+// the same directory as filename, syncs it and renames it to filename
+// afterwards. Readers therefore either see the old or the complete new
+// content, but never a partially written file. The directory is synced
+// after the rename, so the rename survives a crash. If the file already
+// exists its permissions are preserved, otherwise perm is used.
+func WriteAtomic(filename string, perm os.FileMode, write func(w io.Writer) error) (err error) {
+	if fi, sErr := os.Stat(filename); sErr == nil {
+		perm = fi.Mode().Perm()
+	} else if !os.IsNotExist(sErr) {
+		return fmt.Errorf("cannot write '%s': %v", filename, sErr)
+	}
+
+	f, err := ioutil.TempFile(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot write '%s': %v", filename, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+
+	if err = write(f); err != nil {
+		return fmt.Errorf("cannot write '%s': %v", filename, err)
+	}
+	if err = f.Sync(); err != nil {
+		return fmt.Errorf("cannot write '%s': %v", filename, err)
+	}
+	if err = f.Close(); err != nil {
+		return fmt.Errorf("cannot write '%s': %v", filename, err)
+	}
+	// File.Chmod is not supported on Windows, therefore by name.
+	if err = os.Chmod(f.Name(), perm); err != nil {
+		return fmt.Errorf("cannot write '%s': %v", filename, err)
+	}
+	if err = os.Rename(f.Name(), filename); err != nil {
+		return fmt.Errorf("cannot write '%s': %v", filename, err)
+	}
+	if err := syncDir(filepath.Dir(filename)); err != nil {
+		return fmt.Errorf("cannot write '%s': %v", filename, err)
+	}
+	return nil
+}
+
 func Exists(filename string) (bool, error) {
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
 		return false, nil
@@ -0,0 +1,21 @@
+//+build !windows
+
+package io
+
+import (
+	"os"
+)
+
+// syncDir persists the entries of the given directory, e.g. a file which was
+// renamed into it.
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,9 @@
+// +build windows
+
+package io
+
+// syncDir does nothing, because directories cannot be synced on Windows;
+// renames are persisted by NTFS itself.
+func syncDir(string) error {
+	return nil
+}
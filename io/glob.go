@@ -0,0 +1,61 @@
+package io
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// MatchGlob reports whether the slash separated name matches pattern. Besides
+// the syntax of path.Match the pattern could contain ** as a whole segment,
+// which matches any number (including zero) of segments, e.g. "**/*.go" or
+// "build/**".
+func MatchGlob(pattern, name string) (bool, error) {
+	ok, err := matchGlobSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+	if err != nil {
+		return false, fmt.Errorf("illegal pattern '%s': %v", pattern, err)
+	}
+	return ok, nil
+}
+
+// MatchAnyGlob reports whether name matches at least one of the patterns.
+func MatchAnyGlob(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		if ok, err := MatchGlob(pattern, name); err != nil || ok {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+func matchGlobSegments(pattern, name []string) (bool, error) {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if ok, err := matchGlobSegments(pattern[1:], name[i:]); err != nil || ok {
+					return ok, err
+				}
+			}
+			return false, nil
+		}
+		if len(name) == 0 {
+			return false, nil
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false, err
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0, nil
+}
+
+func validateGlobs(patterns []string) error {
+	for _, pattern := range patterns {
+		for _, segment := range strings.Split(pattern, "/") {
+			if _, err := path.Match(segment, segment); err != nil {
+				return fmt.Errorf("illegal pattern '%s': %v", pattern, err)
+			}
+		}
+	}
+	return nil
+}
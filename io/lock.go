@@ -0,0 +1,201 @@
+package io
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// LockSuffix is appended to the path of a locked resource to get the
+	// name of its lock file.
+	LockSuffix = ".lock"
+
+	lockPollInterval    = 200 * time.Millisecond
+	lockMessageInterval = 30 * time.Second
+)
+
+// DefaultLockTimeout is used by Lock and if LockOptions.Timeout is 0.
+var DefaultLockTimeout = 10 * time.Minute
+
+// LockOptions controls how LockWith acquires a lock.
+type LockOptions struct {
+	Timeout time.Duration // Maximum time to wait for the lock; 0 means DefaultLockTimeout, negative means forever
+	Log     *log.Logger   // Receives the messages while waiting for the lock; nil means stderr
+}
+
+// FileLock is a lock acquired by Lock or LockWith. It has to be released
+// using Unlock.
+type FileLock struct {
+	file   string
+	holder lockHolder
+}
+
+type lockHolder struct {
+	Pid     int       `json:"pid"`
+	Host    string    `json:"host"`
+	Created time.Time `json:"created"`
+}
+
+func (instance lockHolder) String() string {
+	return fmt.Sprintf("PID %d on %s (since %s)", instance.Pid, instance.Host, instance.Created.Local().Format(time.RFC3339))
+}
+
+func (instance lockHolder) same(other lockHolder) bool {
+	return instance.Pid == other.Pid && instance.Host == other.Host && instance.Created.Equal(other.Created)
+}
+
+// Lock locks the resource at path for other processes. See LockWith.
+func Lock(path string) (*FileLock, error) {
+	return LockWith(context.Background(), path, LockOptions{})
+}
+
+// LockWith locks the resource at path for other processes by creating
+// <path>.lock, which records the PID and host of this process. If another
+// process holds the lock LockWith waits until it is released, the timeout
+// elapsed or ctx is done. Locks of processes of the same host which are not
+// running anymore are detected as stale and removed.
+func LockWith(ctx context.Context, path string, options LockOptions) (*FileLock, error) {
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = DefaultLockTimeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	l := options.Log
+	if l == nil {
+		l = log.New(os.Stderr, "", 0)
+	}
+
+	host, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("cannot lock '%s': %v", path, err)
+	}
+	result := &FileLock{
+		file:   path + LockSuffix,
+		holder: lockHolder{Pid: os.Getpid(), Host: host, Created: time.Now().UTC().Truncate(time.Second)},
+	}
+	if err := os.MkdirAll(filepath.Dir(result.file), 0755); err != nil {
+		return nil, fmt.Errorf("cannot create parent of '%s': %v", result.file, err)
+	}
+
+	var lastMessage time.Time
+	for {
+		holder, err := result.tryAcquire()
+		if err != nil {
+			return nil, err
+		} else if holder == nil {
+			return result, nil
+		}
+		if holder.Host == host && holder.Pid != os.Getpid() && !processAlive(holder.Pid) {
+			l.Printf("Removing stale lock '%s' of %v, which is not running anymore.", result.file, *holder)
+			if _, err := removeLockOf(result.file, *holder); err != nil {
+				return nil, fmt.Errorf("cannot remove stale lock '%s': %v", result.file, err)
+			}
+			continue
+		}
+		if time.Since(lastMessage) >= lockMessageInterval {
+			l.Printf("Waiting for lock '%s' held by %v...", result.file, *holder)
+			lastMessage = time.Now()
+		}
+		select {
+		case <-ctx.Done():
+			if ctx.Err() == context.DeadlineExceeded {
+				return nil, fmt.Errorf("cannot acquire lock '%s' within %v; it is held by %v", result.file, timeout, *holder)
+			}
+			return nil, fmt.Errorf("cannot acquire lock '%s': %v", result.file, ctx.Err())
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// Unlock releases the lock if it is still held by this process.
+func (instance *FileLock) Unlock() error {
+	if _, err := removeLockOf(instance.file, instance.holder); err != nil {
+		return fmt.Errorf("cannot release lock '%s': %v", instance.file, err)
+	}
+	return nil
+}
+
+// removeLockOf removes the lock file if it is held by the given holder and
+// reports whether it did. To not remove a lock which someone else acquired
+// in the meantime, the lock file is renamed first and the holder is checked
+// afterwards; a lock of someone else is put back.
+func removeLockOf(file string, holder lockHolder) (bool, error) {
+	taken := fmt.Sprintf("%s.%d.%d.release", file, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(file, taken); os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	actual, err := readLockHolder(taken)
+	if err == nil && !actual.same(holder) {
+		// Was removed as stale and acquired by someone else. If the lock was
+		// acquired again in the meantime the taken one is obsolete anyway.
+		if err := os.Link(taken, file); err != nil && !os.IsExist(err) {
+			return false, err
+		}
+		return false, os.Remove(taken)
+	}
+	// Also removes locks with illegal content, which nobody could hold.
+	return true, os.Remove(taken)
+}
+
+// tryAcquire creates the lock file atomically together with its content, by
+// linking a temporary file. If the lock is held by someone else its holder
+// is returned.
+func (instance *FileLock) tryAcquire() (*lockHolder, error) {
+	b, err := json.Marshal(instance.holder)
+	if err != nil {
+		return nil, err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(instance.file), "."+filepath.Base(instance.file)+".*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create lock '%s': %v", instance.file, err)
+	}
+	//noinspection GoUnhandledErrorResult
+	defer os.Remove(f.Name())
+	_, err = f.Write(b)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot create lock '%s': %v", instance.file, err)
+	}
+
+	for {
+		if err := os.Link(f.Name(), instance.file); err == nil {
+			return nil, nil
+		} else if !os.IsExist(err) {
+			return nil, fmt.Errorf("cannot create lock '%s': %v", instance.file, err)
+		}
+		if holder, err := readLockHolder(instance.file); os.IsNotExist(err) {
+			// Released in the meantime; just try again.
+			continue
+		} else if err != nil {
+			return nil, err
+		} else {
+			return holder, nil
+		}
+	}
+}
+
+func readLockHolder(file string) (*lockHolder, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var result lockHolder
+	if err := json.Unmarshal(b, &result); err != nil {
+		return nil, fmt.Errorf("illegal content of lock '%s': %v", file, err)
+	}
+	return &result, nil
+}
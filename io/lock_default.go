@@ -0,0 +1,19 @@
+//+build !windows
+
+package io
+
+import (
+	"os"
+	"syscall"
+)
+
+func processAlive(pid int) bool {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	// Signal 0 only checks if the process exists; EPERM means it exists
+	// but belongs to someone else.
+	err = p.Signal(syscall.Signal(0))
+	return err == nil || err == syscall.EPERM
+}
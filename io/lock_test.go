@@ -0,0 +1,68 @@
+package io
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnlockRemovesOwnLock(t *testing.T) {
+	base := tempDir(t)
+	//noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(base)
+	path := filepath.Join(base, "resource")
+
+	lock, err := Lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := FileExists(path + LockSuffix); err != nil || !ok {
+		t.Fatalf("expected lock file to exist (err: %v)", err)
+	}
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	assertFiles(t, base)
+
+	// Releasing it twice does no harm.
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUnlockKeepsLockOfOthers(t *testing.T) {
+	base := tempDir(t)
+	//noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(base)
+	path := filepath.Join(base, "resource")
+
+	lock, err := Lock(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Simulates that the lock was removed as stale and acquired by someone
+	// else.
+	other := &FileLock{file: lock.file, holder: lock.holder}
+	other.holder.Pid++
+	if err := os.Remove(lock.file); err != nil {
+		t.Fatal(err)
+	}
+	if holder, err := other.tryAcquire(); err != nil || holder != nil {
+		t.Fatalf("expected lock to be acquired but got %v (err: %v)", holder, err)
+	}
+
+	if err := lock.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	if holder, err := readLockHolder(lock.file); err != nil {
+		t.Fatal(err)
+	} else if !holder.same(other.holder) {
+		t.Errorf("expected lock of %v but got %v", other.holder, *holder)
+	}
+	assertFiles(t, base, "resource"+LockSuffix)
+
+	if err := other.Unlock(); err != nil {
+		t.Fatal(err)
+	}
+	assertFiles(t, base)
+}
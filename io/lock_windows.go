@@ -0,0 +1,24 @@
+// +build windows
+
+package io
+
+import (
+	"syscall"
+)
+
+const stillActive = 259
+
+func processAlive(pid int) bool {
+	h, err := syscall.OpenProcess(syscall.PROCESS_QUERY_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// ERROR_ACCESS_DENIED means it exists but belongs to someone else.
+		return err == syscall.ERROR_ACCESS_DENIED
+	}
+	//noinspection GoUnhandledErrorResult
+	defer syscall.CloseHandle(h)
+	var code uint32
+	if err := syscall.GetExitCodeProcess(h, &code); err != nil {
+		return true
+	}
+	return code == stillActive
+}
@@ -0,0 +1,62 @@
+package io
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// IsTerminal returns true if the given file is connected to a terminal.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// Other character devices like /dev/null are no terminals.
+	return isTerminal(f)
+}
+
+// Prompt writes the given message to stderr and reads one line from stdin.
+func Prompt(message string) (string, error) {
+	_, _ = fmt.Fprint(os.Stderr, message)
+	return readLine(os.Stdin)
+}
+
+// PromptHidden is like Prompt but does not echo the input, if stdin is a
+// terminal.
+func PromptHidden(message string) (string, error) {
+	_, _ = fmt.Fprint(os.Stderr, message)
+	if !IsTerminal(os.Stdin) {
+		return readLine(os.Stdin)
+	}
+	restore, err := disableEcho(os.Stdin)
+	if err != nil {
+		return "", fmt.Errorf("cannot disable echo of terminal: %v", err)
+	}
+	result, err := readLine(os.Stdin)
+	restore()
+	_, _ = fmt.Fprintln(os.Stderr)
+	return result, err
+}
+
+// readLine reads byte by byte to not consume more than one line of f.
+func readLine(f io.Reader) (string, error) {
+	var result []byte
+	b := make([]byte, 1)
+	for {
+		n, err := f.Read(b)
+		if n > 0 {
+			if b[0] == '\n' {
+				break
+			}
+			result = append(result, b[0])
+		}
+		if err == io.EOF && len(result) > 0 {
+			break
+		} else if err != nil {
+			return "", fmt.Errorf("cannot read from terminal: %v", err)
+		}
+	}
+	return strings.TrimSuffix(string(result), "\r"), nil
+}
@@ -0,0 +1,27 @@
+//+build !windows
+
+package io
+
+import (
+	"os"
+	"os/exec"
+)
+
+func isTerminal(f *os.File) bool {
+	return stty(f, "-g") == nil
+}
+
+func disableEcho(f *os.File) (restore func(), err error) {
+	if err := stty(f, "-echo"); err != nil {
+		return nil, err
+	}
+	return func() {
+		_ = stty(f, "echo")
+	}, nil
+}
+
+func stty(f *os.File, args ...string) error {
+	c := exec.Command("stty", args...)
+	c.Stdin = f
+	return c.Run()
+}
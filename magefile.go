@@ -19,6 +19,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/echocat/mageplus/git"
 	"github.com/magefile/mage/mg"
 	"github.com/magefile/mage/sh"
 )
@@ -27,8 +28,10 @@ var (
 	errLog     = log.New(os.Stderr, "", 0)
 	releaseTag = regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+$`)
 	version    = func() (result string) {
-		if s, err := sh.Output("git", "describe", "--tags"); err == nil {
-			result = s
+		if d, err := git.Describe(); err == nil {
+			// Uncommitted changes do not affect the version.
+			d.Dirty = false
+			result = d.String()
 		}
 		if s, ok := os.LookupEnv("TAG"); ok {
 			result = s
@@ -141,7 +144,7 @@ func flags() string {
 
 // hash returns the git hash for the current repo or "" if none.
 func hash() string {
-	hash, _ := sh.Output("git", "rev-parse", "--short", "HEAD")
+	hash, _ := git.ShortCommit()
 	return hash
 }
 
@@ -157,6 +160,12 @@ func GenerateWrapperSources() error {
 	}
 	wcf = []byte(strings.ReplaceAll(string(wcf), "\r\n", "\n"))
 
+	wpf, err := ioutil.ReadFile("wrapper/mageplusw.ps1")
+	if err != nil {
+		return err
+	}
+	wpf = []byte(strings.ReplaceAll(string(wpf), "\r\n", "\n"))
+
 	currentContent, err := ioutil.ReadFile("wrapper/resources.go")
 	if err != nil && !os.IsNotExist(err) {
 		return err
@@ -166,7 +175,8 @@ func GenerateWrapperSources() error {
 		"func init() {\n"+
 		"\tunixScript = `%s`\n"+
 		"\twindowsScript = `%s`\n"+
-		"}\n", base64.RawURLEncoding.EncodeToString(wf), base64.RawURLEncoding.EncodeToString(wcf),
+		"\tpowershellScript = `%s`\n"+
+		"}\n", base64.RawURLEncoding.EncodeToString(wf), base64.RawURLEncoding.EncodeToString(wcf), base64.RawURLEncoding.EncodeToString(wpf),
 	))
 	if bytes.Equal(currentContent, newContent) {
 		return nil
@@ -0,0 +1,35 @@
+package mageplus
+
+import (
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"log"
+	"path/filepath"
+)
+
+// checksum writes the checksums of all files inside of inv.Dir matching the
+// glob patterns of inv.Args into the checksums file of inv.Algorithm or, with
+// -check, verifies the files against it.
+func checksum(inv Invocation, out *log.Logger) error {
+	file := filepath.Join(inv.Dir, inv.Algorithm.FileName())
+	if inv.Check {
+		if err := mio.VerifyChecksumsFile(file); err != nil {
+			return err
+		}
+		out.Println(file, "verified")
+		return nil
+	}
+
+	checksums, err := mio.GenerateChecksums(inv.Algorithm, inv.Dir, inv.Args...)
+	if err != nil {
+		return err
+	}
+	if len(checksums) == 0 {
+		return fmt.Errorf("there are no files matching %q inside of '%s'", inv.Args, inv.Dir)
+	}
+	if err := checksums.Write(file); err != nil {
+		return err
+	}
+	out.Println(file, "created")
+	return nil
+}
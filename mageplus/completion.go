@@ -0,0 +1,170 @@
+package mageplus
+
+import (
+	"flag"
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"github.com/echocat/mageplus/sdk"
+	"github.com/echocat/mageplus/wrapper"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+)
+
+const (
+	completionValues = ":values"
+	completionFiles  = ":files"
+	completionDirs   = ":dirs"
+)
+
+var (
+	CompletionScripts = map[string]string{
+		"bash":       bashCompletionTpl,
+		"zsh":        zshCompletionTpl,
+		"fish":       fishCompletionTpl,
+		"powershell": powershellCompletionTpl,
+	}
+
+	// completionOfFlagValues contains for every flag which requires a value
+	// how the candidates of the value are determined. Flags which are not
+	// listed here are not completed at all.
+	completionOfFlagValues = map[string]func(Invocation) (string, []string){
+		"d":          completeWith(completionDirs),
+		"compile":    completeWith(completionFiles),
+		"gocmd":      completeWith(completionFiles),
+		"goos":       completeWith(completionValues, "android", "darwin", "dragonfly", "freebsd", "linux", "netbsd", "openbsd", "plan9", "solaris", "windows"),
+		"goarch":     completeWith(completionValues, "386", "amd64", "arm", "arm64", "mips", "mips64", "mips64le", "mipsle", "ppc64", "ppc64le", "s390x", "wasm"),
+		"completion": completeWith(completionValues, completionShells()...),
+		"format":     completeWith(completionValues, Formats...),
+		"flavours":   completeWith(completionValues, completionFlavours()...),
+		"algorithm":  completeWith(completionValues, completionAlgorithms()...),
+		"go":         completeSdkVersions,
+	}
+
+	hiddenFlags = map[string]bool{
+		"complete": true,
+	}
+)
+
+func writeCompletionScript(w io.Writer, shell string) error {
+	script, ok := CompletionScripts[shell]
+	if !ok {
+		return fmt.Errorf("unsupported shell '%s' for completion; supported are: %s", shell, strings.Join(completionShells(), ", "))
+	}
+	_, err := io.WriteString(w, script)
+	return err
+}
+
+// writeCompletions writes the completion candidates for inv.CompleteWord to
+// the given writer. The first line is always the directive which tells the
+// completion script how to handle the candidates of the following lines.
+func writeCompletions(w io.Writer, inv Invocation) error {
+	directive, candidates := completionCandidates(inv)
+	if _, err := fmt.Fprintln(w, directive); err != nil {
+		return err
+	}
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, inv.CompleteWord) {
+			if _, err := fmt.Fprintln(w, candidate); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func completionCandidates(inv Invocation) (string, []string) {
+	// Parse the words before the current one to respect flags like -d which
+	// modify the target of the completion.
+	words := inv.Args
+	sub, _, _ := Parse(ioutil.Discard, ioutil.Discard, words)
+	sub.Stderr = ioutil.Discard
+	sub.Stdin = inv.Stdin
+	if sub.CacheDir == "" {
+		sub.CacheDir = inv.CacheDir
+	}
+
+	if len(words) > 0 {
+		if f := lookupFlag(sub.flags, words[len(words)-1]); f != nil && !isBoolFlag(f) {
+			if completion, ok := completionOfFlagValues[f.Name]; ok {
+				return completion(sub)
+			}
+			return completionValues, nil
+		}
+	}
+
+	if strings.HasPrefix(inv.CompleteWord, "-") {
+		var result []string
+		sub.flags.VisitAll(func(f *flag.Flag) {
+			if !hiddenFlags[f.Name] {
+				result = append(result, "-"+f.Name)
+			}
+		})
+		return completionValues, result
+	}
+
+	// The completion must never download the golang SDK, so only the targets
+	// of the last listing are used.
+	targets, err := CompletionTargets(sub)
+	if err != nil {
+		debug.Println("cannot list targets for completion:", err)
+		return completionValues, nil
+	}
+	return completionValues, targets.NamesAndAliases()
+}
+
+func lookupFlag(fs *flag.FlagSet, word string) *flag.Flag {
+	if fs == nil || !strings.HasPrefix(word, "-") || strings.Contains(word, "=") {
+		return nil
+	}
+	return fs.Lookup(strings.TrimLeft(word, "-"))
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	if bf, ok := f.Value.(interface{ IsBoolFlag() bool }); ok {
+		return bf.IsBoolFlag()
+	}
+	return false
+}
+
+func completeWith(directive string, candidates ...string) func(Invocation) (string, []string) {
+	return func(Invocation) (string, []string) {
+		return directive, candidates
+	}
+}
+
+// completeSdkVersions offers the versions of the golang SDKs which are
+// available without downloading anything.
+func completeSdkVersions(Invocation) (string, []string) {
+	versions, err := sdk.KnownVersions()
+	if err != nil {
+		debug.Println("cannot list golang SDK versions for completion:", err)
+	}
+	return completionValues, versions
+}
+
+func completionShells() []string {
+	result := make([]string, 0, len(CompletionScripts))
+	for shell := range CompletionScripts {
+		result = append(result, shell)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func completionAlgorithms() []string {
+	result := make([]string, len(mio.ChecksumAlgorithms))
+	for i, algorithm := range mio.ChecksumAlgorithms {
+		result[i] = string(algorithm)
+	}
+	return result
+}
+
+func completionFlavours() []string {
+	result := make([]string, len(wrapper.AllFlavours))
+	for i, flavour := range wrapper.AllFlavours {
+		result[i] = string(flavour)
+	}
+	return result
+}
@@ -0,0 +1,98 @@
+package mageplus
+
+// All completion scripts call back into the binary (or wrapper) which was
+// used on the command line with -complete=<current word> -- <previous words>.
+// The first line of the response is a directive (:values, :files or :dirs)
+// and all following lines are the candidates.
+
+var bashCompletionTpl = `# bash completion for mageplus and mageplusw
+#
+# Load it in the current shell with:
+#   source <(mageplus -completion bash)
+
+_mageplus_complete() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    local out
+    out=($("${COMP_WORDS[0]}" -complete="${cur}" -- "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null))
+    case "${out[0]}" in
+        :files) COMPREPLY=($(compgen -f -- "${cur}"));;
+        :dirs)  COMPREPLY=($(compgen -d -- "${cur}"));;
+        *)      COMPREPLY=($(compgen -W "${out[*]:1}" -- "${cur}"));;
+    esac
+}
+
+complete -o filenames -F _mageplus_complete mageplus mageplusw ./mageplusw
+`
+
+var zshCompletionTpl = `#compdef mageplus mageplusw ./mageplusw
+#
+# zsh completion for mageplus and mageplusw
+#
+# Load it in the current shell with:
+#   source <(mageplus -completion zsh)
+
+_mageplus_complete() {
+    local -a out
+    out=("${(@f)$("${words[1]}" -complete="${words[CURRENT]}" -- "${(@)words[2,CURRENT-1]}" 2>/dev/null)}")
+    case "${out[1]}" in
+        :files) _files;;
+        :dirs)  _files -/;;
+        *)      compadd -- "${(@)out[2,-1]}";;
+    esac
+}
+
+compdef _mageplus_complete mageplus mageplusw ./mageplusw
+`
+
+var fishCompletionTpl = `# fish completion for mageplus and mageplusw
+#
+# Load it in the current shell with:
+#   mageplus -completion fish | source
+
+function __mageplus_complete
+    set -l tokens (commandline -opc)
+    set -l command $tokens[1]
+    set -e tokens[1]
+    set -l current (commandline -ct)
+    set -l out ($command -complete="$current" -- $tokens 2>/dev/null)
+    switch "$out[1]"
+        case :files
+            __fish_complete_path "$current"
+        case :dirs
+            __fish_complete_directories "$current"
+        case '*'
+            set -e out[1]
+            printf '%s\n' $out
+    end
+end
+
+complete -c mageplus -f -a '(__mageplus_complete)'
+complete -c mageplusw -f -a '(__mageplus_complete)'
+`
+
+var powershellCompletionTpl = `# PowerShell completion for mageplus and mageplusw
+#
+# Load it in the current shell with:
+#   mageplus -completion powershell | Out-String | Invoke-Expression
+
+Register-ArgumentCompleter -Native -CommandName 'mageplus', 'mageplus.exe', 'mageplusw', 'mageplusw.cmd', 'mageplusw.ps1' -ScriptBlock {
+    param($wordToComplete, $commandAst, $cursorPosition)
+
+    $elements = @($commandAst.CommandElements)
+    $command = $elements[0].ToString()
+    $arguments = @($elements |
+        Select-Object -Skip 1 |
+        Where-Object { $_.Extent.EndOffset -lt $cursorPosition } |
+        ForEach-Object { $_.ToString() })
+
+    $out = @(& $command "-complete=$wordToComplete" -- @arguments 2>$null)
+    if ($out.Count -eq 0 -or $out[0] -eq ':files' -or $out[0] -eq ':dirs') {
+        # Fall back to the default path completion of PowerShell.
+        return
+    }
+    $out | Select-Object -Skip 1 | Where-Object { $_ -like "$wordToComplete*" } | ForEach-Object {
+        [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+    }
+}
+`
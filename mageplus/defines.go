@@ -0,0 +1,53 @@
+package mageplus
+
+import (
+	"fmt"
+	"github.com/echocat/mageplus/values"
+	"os"
+	"sort"
+	"strings"
+)
+
+// defines collects the repeatable -D name=value flags.
+type defines map[string]string
+
+func (instance defines) String() string {
+	parts := make([]string, 0, len(instance))
+	for _, name := range instance.names() {
+		parts = append(parts, name+"="+instance[name])
+	}
+	return strings.Join(parts, " ")
+}
+
+func (instance defines) Set(plain string) error {
+	parts := strings.SplitN(plain, "=", 2)
+	if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+		return fmt.Errorf("expected name=value but got: %s", plain)
+	}
+	instance[strings.TrimSpace(parts[0])] = parts[1]
+	return nil
+}
+
+func (instance defines) names() []string {
+	result := make([]string, 0, len(instance))
+	for name := range instance {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// applyDefines sets the given variables in the environment, overwriting
+// existing ones (including those from dotenv files), and records their names
+// in values.EnvDefines, so declared values could report them as origin.
+func applyDefines(candidates defines) error {
+	if len(candidates) == 0 {
+		return nil
+	}
+	for name, value := range candidates {
+		if err := os.Setenv(name, value); err != nil {
+			return fmt.Errorf("cannot apply -D %s: %v", name, err)
+		}
+	}
+	return os.Setenv(values.EnvDefines, strings.Join(candidates.names(), ","))
+}
@@ -0,0 +1,161 @@
+package mageplus
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"github.com/echocat/mageplus/values"
+	"github.com/joho/godotenv"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// envEncrypt encrypts the plain dotenv file given as argument
+// (or values.PlainDotEnvFile) into values.EncryptedDotEnvFile.
+func envEncrypt(inv Invocation, out *log.Logger) error {
+	source := values.PlainDotEnvFile
+	if len(inv.Args) > 0 {
+		source = inv.Args[0]
+	}
+	plain, err := ioutil.ReadFile(source)
+	if err != nil {
+		return fmt.Errorf("cannot read '%s': %v", source, err)
+	}
+	if _, err := godotenv.Parse(bytes.NewReader(plain)); err != nil {
+		return fmt.Errorf("cannot parse '%s': %v", source, err)
+	}
+
+	key, _, err := encryptedDotEnvKey()
+	if err != nil {
+		return err
+	}
+	if err := writeEncryptedDotEnv(plain, key); err != nil {
+		return err
+	}
+	out.Printf("%s encrypted to %s", source, values.EncryptedDotEnvFile)
+	return nil
+}
+
+// envEdit decrypts values.EncryptedDotEnvFile into a temporary file, opens it
+// using the editor of the user and encrypts it again afterwards.
+func envEdit(_ Invocation, out *log.Logger) error {
+	key, existing, err := encryptedDotEnvKey()
+	if err != nil {
+		return err
+	}
+	var plain []byte
+	if existing != nil {
+		if plain, err = values.Decrypt(existing, key); err != nil {
+			return fmt.Errorf("cannot decrypt '%s': %v", values.EncryptedDotEnvFile, err)
+		}
+	}
+
+	f, err := ioutil.TempFile("", "mageplus-env-*.env")
+	if err != nil {
+		return fmt.Errorf("cannot create temporary file: %v", err)
+	}
+	//noinspection GoUnhandledErrorResult
+	defer os.Remove(f.Name())
+	_, err = f.Write(plain)
+	mio.CloseQuietly(f)
+	if err != nil {
+		return fmt.Errorf("cannot write '%s': %v", f.Name(), err)
+	}
+
+	if err := runEditor(f.Name()); err != nil {
+		return err
+	}
+
+	edited, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return fmt.Errorf("cannot read '%s': %v", f.Name(), err)
+	}
+	if existing != nil && bytes.Equal(plain, edited) {
+		out.Println(values.EncryptedDotEnvFile, "unchanged")
+		return nil
+	}
+	if _, err := godotenv.Parse(bytes.NewReader(edited)); err != nil {
+		return fmt.Errorf("cannot parse edited content; %s unchanged: %v", values.EncryptedDotEnvFile, err)
+	}
+	if err := writeEncryptedDotEnv(edited, key); err != nil {
+		return err
+	}
+	out.Println(values.EncryptedDotEnvFile, "updated")
+	return nil
+}
+
+// encryptedDotEnvKey returns the key for values.EncryptedDotEnvFile together
+// with its current content. If the file exists the key has to match it,
+// otherwise a new key is requested which has to be repeated.
+func encryptedDotEnvKey() (key string, existing []byte, err error) {
+	existing, err = ioutil.ReadFile(values.EncryptedDotEnvFile)
+	if os.IsNotExist(err) {
+		existing = nil
+	} else if err != nil {
+		return "", nil, fmt.Errorf("cannot read '%s': %v", values.EncryptedDotEnvFile, err)
+	}
+
+	if key, err = values.DotEnvKey(false); err == values.ErrNoDotEnvKey && existing == nil && mio.IsTerminal(os.Stdin) {
+		if key, err = mio.PromptHidden(fmt.Sprintf("New key of %s: ", values.EncryptedDotEnvFile)); err != nil {
+			return "", nil, err
+		}
+		if key == "" {
+			return "", nil, values.ErrNoDotEnvKey
+		}
+		if repeated, err := mio.PromptHidden("Repeat the key: "); err != nil {
+			return "", nil, err
+		} else if repeated != key {
+			return "", nil, errors.New("the keys do not match")
+		}
+	} else if err == values.ErrNoDotEnvKey {
+		if key, err = values.DotEnvKey(true); err != nil {
+			return "", nil, err
+		}
+	} else if err != nil {
+		return "", nil, err
+	}
+
+	if existing != nil {
+		if _, err := values.Decrypt(existing, key); err != nil {
+			return "", nil, fmt.Errorf("cannot decrypt '%s': %v", values.EncryptedDotEnvFile, err)
+		}
+	}
+	return key, existing, nil
+}
+
+func writeEncryptedDotEnv(plain []byte, key string) error {
+	encrypted, err := values.Encrypt(plain, key)
+	if err != nil {
+		return fmt.Errorf("cannot encrypt '%s': %v", values.EncryptedDotEnvFile, err)
+	}
+	// An interrupted write must not destroy the only copy of the secrets.
+	return mio.WriteFileAtomic(values.EncryptedDotEnvFile, encrypted, 0644)
+}
+
+func runEditor(file string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+		if runtime.GOOS == "windows" {
+			editor = "notepad"
+		}
+	}
+	// The editor could contain arguments like: code --wait
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], file)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor '%s' failed: %v", editor, err)
+	}
+	return nil
+}
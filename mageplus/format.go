@@ -0,0 +1,121 @@
+package mageplus
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	rdebug "runtime/debug"
+)
+
+const (
+	FormatText = "text"
+	FormatJson = "json"
+
+	mageModulePath = "github.com/magefile/mage"
+)
+
+var Formats = []string{FormatText, FormatJson}
+
+// VersionInfo describes the mageplus binary and the golang SDK it would use.
+type VersionInfo struct {
+	GitTag      string   `json:"gitTag"`
+	Commit      string   `json:"commit"`
+	BuildDate   string   `json:"buildDate"`
+	GoVersion   string   `json:"goVersion"`
+	MageVersion string   `json:"mageVersion"`
+	Sdk         *SdkInfo `json:"sdk"`
+}
+
+// SdkInfo describes the golang SDK which was resolved for the invocation.
+type SdkInfo struct {
+	Version  string `json:"version"`
+	Os       string `json:"os"`
+	Arch     string `json:"arch"`
+	Root     string `json:"root"`
+	GoBinary string `json:"goBinary"`
+}
+
+// CurrentVersionInfo returns the VersionInfo of this binary. The SDK is only
+// resolved if the invocation requires to ensure the SDK.
+func CurrentVersionInfo(inv Invocation) (VersionInfo, error) {
+	result := VersionInfo{
+		GitTag:      gitTag,
+		Commit:      commitHash,
+		BuildDate:   timestamp,
+		GoVersion:   runtime.Version(),
+		MageVersion: mageVersion(),
+	}
+	if inv.EnsureSdk {
+		s, err := ResolveSdk(inv)
+		if err != nil {
+			return VersionInfo{}, err
+		}
+		result.Sdk = &SdkInfo{
+			Version:  s.Version.String(),
+			Os:       s.Os,
+			Arch:     s.Arch,
+			Root:     s.Root,
+			GoBinary: s.GoBinary,
+		}
+	}
+	return result, nil
+}
+
+func mageVersion() string {
+	if bi, ok := rdebug.ReadBuildInfo(); ok {
+		for _, dep := range bi.Deps {
+			if dep.Path == mageModulePath {
+				if dep.Replace != nil {
+					return dep.Replace.Version
+				}
+				return dep.Version
+			}
+		}
+	}
+	return notSet
+}
+
+func writeJson(w io.Writer, v interface{}) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("cannot encode output as json: %v", err)
+	}
+	return nil
+}
+
+func writeVersionJson(w io.Writer, inv Invocation) error {
+	info, err := CurrentVersionInfo(inv)
+	if err != nil {
+		return err
+	}
+	return writeJson(w, info)
+}
+
+func writeTargetsJson(w io.Writer, inv Invocation) error {
+	if err := EnsureSdkIfRequired(inv); err != nil {
+		return err
+	}
+	targets, err := ListTargets(inv)
+	if err != nil {
+		return err
+	}
+	return writeJson(w, targets)
+}
+
+func writeTargetJson(w io.Writer, inv Invocation, name string) error {
+	if err := EnsureSdkIfRequired(inv); err != nil {
+		return err
+	}
+	targets, err := ListTargets(inv)
+	if err != nil {
+		return err
+	}
+	target := targets.Find(name)
+	if target == nil {
+		return fmt.Errorf("unknown target specified: %q", name)
+	}
+	return writeJson(w, target)
+}
@@ -0,0 +1,132 @@
+package mageplus
+
+import (
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"github.com/magefile/mage/mage"
+	"github.com/magefile/mage/parse"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// mainFile is the name of the main file mage generates next to the
+// magefiles.
+const mainFile = "mage_output_file.go"
+
+// invoke behaves like mage.Invoke but compiles the magefile while holding
+// the lock of the cache directory, which -clean takes too. The lock is
+// released before the compiled magefile runs; otherwise every other
+// invocation sharing the cache directory (including the ones started by
+// targets) would have to wait until it finishes. Removing a running binary
+// does not affect it on Unix systems and fails on Windows. If CompileOut is
+// set the magefile is only compiled to it, like with -compile.
+func invoke(inv mage.Invocation) int {
+	errlog := log.New(inv.Stderr, "", 0)
+	if inv.GoCmd == "" {
+		inv.GoCmd = "go"
+	}
+	if inv.Dir == "" {
+		inv.Dir = "."
+	}
+
+	files, err := mage.Magefiles(inv.Dir, inv.GOOS, inv.GOARCH, inv.GoCmd, inv.Stderr, inv.Debug)
+	if err != nil {
+		errlog.Println("Error determining list of magefiles:", err)
+		return 1
+	}
+	if len(files) == 0 {
+		errlog.Println("No .go files marked with the mage build tag in this directory.")
+		return 1
+	}
+	debug.Printf("found magefiles: %s", strings.Join(files, ", "))
+	exe := inv.CompileOut
+	if exe == "" {
+		if exe, err = mage.ExeName(inv.GoCmd, inv.CacheDir, files); err != nil {
+			errlog.Println("Error getting exe name:", err)
+			return 1
+		}
+	}
+	debug.Println("output exe is", exe)
+
+	if err := compileLocked(inv, files, exe); err != nil {
+		errlog.Println("Error:", err)
+		return 1
+	}
+	if inv.CompileOut != "" {
+		return 0
+	}
+	return mage.RunCompiled(inv, exe, errlog)
+}
+
+// compileLocked compiles the given magefiles to exe while holding the lock
+// of the cache directory. Like mage does, an existing exe is only reused if
+// GOCACHE is not used and it is not forced to be recreated.
+func compileLocked(inv mage.Invocation, files []string, exe string) error {
+	lock, err := mio.Lock(inv.CacheDir)
+	if err != nil {
+		return err
+	}
+	//noinspection GoUnhandledErrorResult
+	defer lock.Unlock()
+
+	if inv.Force {
+		debug.Println("ignoring existing executable")
+	} else if reuse, err := reusesCompiled(inv); err != nil {
+		return err
+	} else if ok, _ := mio.FileExists(exe); reuse && ok {
+		debug.Println("running existing exe")
+		return nil
+	} else if reuse {
+		debug.Println("no existing exe, creating new")
+	}
+
+	fileNames := make([]string, len(files))
+	for i, file := range files {
+		fileNames[i] = filepath.Base(file)
+	}
+	if inv.Debug {
+		parse.EnableDebug()
+	}
+	debug.Println("parsing files")
+	info, err := parse.PrimaryPackage(inv.GoCmd, inv.Dir, fileNames)
+	if err != nil {
+		return fmt.Errorf("cannot parse magefiles: %v", err)
+	}
+	binaryName := "mage"
+	if inv.CompileOut != "" {
+		binaryName = filepath.Base(inv.CompileOut)
+	}
+	main := filepath.Join(inv.Dir, mainFile)
+	if err := mage.GenerateMainfile(binaryName, main, info); err != nil {
+		return err
+	}
+	if inv.Keep {
+		debug.Println("keeping mainfile")
+	} else {
+		//noinspection GoUnhandledErrorResult
+		defer os.Remove(main)
+	}
+	return mage.Compile(inv.GOOS, inv.GOARCH, inv.Dir, inv.GoCmd, exe, append(files, main), inv.Debug, inv.Stderr, inv.Stdout)
+}
+
+// reusesCompiled reports whether an existing compiled magefile could be
+// used. If GOCACHE is used the magefile is always compiled to catch changes
+// of its dependencies; go build itself skips unchanged packages then.
+func reusesCompiled(inv mage.Invocation) (bool, error) {
+	if inv.HashFast {
+		debug.Println("user has set MAGEFILE_HASHFAST, so we'll ignore GOCACHE")
+		return true, nil
+	}
+	out, err := exec.Command(inv.GoCmd, "env", "GOCACHE").Output()
+	if err != nil {
+		return false, fmt.Errorf("cannot run %s env GOCACHE: %v", inv.GoCmd, err)
+	}
+	if strings.TrimSpace(string(out)) != "" {
+		debug.Println("go build cache exists, will ignore any compiled binary")
+		return false, nil
+	}
+	return true, nil
+}
@@ -6,9 +6,9 @@ import (
 	"fmt"
 	mio "github.com/echocat/mageplus/io"
 	"github.com/echocat/mageplus/sdk"
+	"github.com/echocat/mageplus/uptodate"
 	"github.com/echocat/mageplus/values"
 	"github.com/echocat/mageplus/wrapper"
-	"github.com/joho/godotenv"
 	"github.com/magefile/mage/mage"
 	"github.com/magefile/mage/mg"
 	"io"
@@ -17,13 +17,20 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"text/template"
 )
 
 const (
-	initFile              = "magefile.go"
-	Wrapper  mage.Command = 1000
-	notSet                = "<not set>"
+	initFile                = "magefile.go"
+	Wrapper    mage.Command = 1000
+	Completion mage.Command = 1001
+	Complete   mage.Command = 1002
+	Update     mage.Command = 1003
+	EnvEncrypt mage.Command = 1004
+	EnvEdit    mage.Command = 1005
+	Checksum   mage.Command = 1006
+	notSet                  = "<not set>"
 )
 
 var (
@@ -32,7 +39,9 @@ var (
 	timestamp  = notSet
 	gitTag     = notSet
 
-	DotEnvFilesCandidates = []string{".env.mage", ".env.build", ".env", ".env.example"}
+	// Deprecated: Use values.DotEnvFilesCandidates. Both share the same
+	// elements, but only values.DotEnvFilesCandidates could be reassigned.
+	DotEnvFilesCandidates = values.DotEnvFilesCandidates
 
 	debug = log.New(ioutil.Discard, "DEBUG: ", log.Ltime|log.Lmicroseconds)
 
@@ -41,7 +50,20 @@ var (
 
 type Invocation struct {
 	mage.Invocation
-	EnsureSdk bool // If true SDK will be ensured and on demand downloaded
+	EnsureSdk       bool                  // If true SDK will be ensured and on demand downloaded
+	GoVersion       string                // If set the SDK will be ensured in exactly this version
+	CompletionShell string                // Shell to print the completion script for
+	CompleteWord    string                // Word to print the completion candidates for
+	Format          string                // Format of the output of -l, -h <target> and -version
+	DryRun          bool                  // If true -update will only report what it would change
+	Check           bool                  // If true -wrapper will only check if the wrapper is up to date; -checksum will only verify the checksums
+	WrapperBaseUrl  string                // Base URL of the releases the wrapper should download mageplus from
+	WrapperFlavours []wrapper.Flavour     // Flavours of the wrapper scripts -wrapper should create
+	Defines         map[string]string     // Variables set by -D name=value which take precedence over the environment
+	Algorithm       mio.ChecksumAlgorithm // Algorithm of the checksums of -checksum
+	ForceTargets    bool                  // If true targets of package uptodate run even if they are up to date
+
+	flags *flag.FlagSet
 }
 
 // Main is the entrypoint for running mage.  It exists external to mage's main
@@ -68,18 +90,26 @@ func ParseAndRun(stdout, stderr io.Writer, stdin io.Reader, args []string) int {
 		return 2
 	}
 
-	if files, err := resolveDotEnvFiles(); err != nil {
+	// Only ask for the key of encrypted dotenv files if targets are executed.
+	promptForKey := cmd == mage.None && !inv.List && !inv.Help
+	if err := values.LoadDotEnv(promptForKey); err != nil {
 		errlog.Println("Error:", err)
 		return 2
-	} else if len(files) == 0 {
-		// Ignore
-	} else if err := godotenv.Load(files...); err != nil {
+	}
+	if err := applyDefines(inv.Defines); err != nil {
 		errlog.Println("Error:", err)
 		return 2
 	}
 
 	switch cmd {
 	case mage.Version:
+		if inv.Format == FormatJson {
+			if err := writeVersionJson(stdout, inv); err != nil {
+				errlog.Println("Error:", err)
+				return 1
+			}
+			return 0
+		}
 		out.Println("Mage Build Tool", gitTag)
 		out.Println("Build Date:", timestamp)
 		out.Println("Commit:", commitHash)
@@ -98,14 +128,88 @@ func ParseAndRun(stdout, stderr io.Writer, stdin io.Reader, args []string) int {
 		if version == notSet {
 			version = values.RequireValue("MAGEPLUS_VERSION")
 		}
-		if err := wrapper.Write(inv.Dir, version); err != nil {
+		if inv.GoVersion != "" {
+			if _, err := sdk.NewDownloadDiscovery(inv.GoVersion); err != nil {
+				errlog.Println("Error:", err)
+				return 1
+			}
+		}
+		options := wrapper.Options{
+			Version:        version,
+			GoVersion:      inv.GoVersion,
+			ReleasesUrl:    inv.WrapperBaseUrl,
+			Flavours:       inv.WrapperFlavours,
+			FetchChecksums: true,
+		}
+		if inv.Check {
+			if len(options.Flavours) == 0 {
+				// Only check the scripts the wrapper was created with.
+				flavours, err := wrapper.ExistingFlavours(inv.Dir)
+				if err != nil {
+					errlog.Println("Error:", err)
+					return 1
+				}
+				options.Flavours = flavours
+			}
+			drifts, err := wrapper.Check(inv.Dir, options)
+			if err != nil {
+				errlog.Println("Error:", err)
+				return 1
+			}
+			for _, drift := range drifts {
+				out.Println(drift)
+			}
+			if len(drifts) > 0 {
+				errlog.Println("Error: wrapper is not up to date; run: mageplus -wrapper")
+				return 1
+			}
+			out.Println("mageplusw", "is up to date")
+			return 0
+		}
+		if err := wrapper.WriteWith(inv.Dir, options); err != nil {
 			errlog.Println("Error:", err)
 			return 1
 		}
 		out.Println("mageplusw", "created")
 		return 0
+	case Completion:
+		if err := writeCompletionScript(stdout, inv.CompletionShell); err != nil {
+			errlog.Println("Error:", err)
+			return 1
+		}
+		return 0
+	case Complete:
+		if err := writeCompletions(stdout, inv); err != nil {
+			errlog.Println("Error:", err)
+			return 1
+		}
+		return 0
+	case Update:
+		if err := update(inv, out); err != nil {
+			errlog.Println("Error:", err)
+			return 1
+		}
+		return 0
+	case EnvEncrypt:
+		if err := envEncrypt(inv, out); err != nil {
+			errlog.Println("Error:", err)
+			return 1
+		}
+		return 0
+	case EnvEdit:
+		if err := envEdit(inv, out); err != nil {
+			errlog.Println("Error:", err)
+			return 1
+		}
+		return 0
+	case Checksum:
+		if err := checksum(inv, out); err != nil {
+			errlog.Println("Error:", err)
+			return 1
+		}
+		return 0
 	case mage.Clean:
-		if err := removeContents(inv.CacheDir); err != nil {
+		if err := cleanCacheDir(inv.CacheDir); err != nil {
 			out.Println("Error:", err)
 			return 1
 		}
@@ -116,23 +220,85 @@ func ParseAndRun(stdout, stderr io.Writer, stdin io.Reader, args []string) int {
 			errlog.Println("Error:", err)
 			return 1
 		}
-		return mage.Invoke(inv.Invocation)
+		return invoke(inv.Invocation)
 	case mage.None:
+		if inv.Format == FormatJson {
+			var err error
+			if inv.List {
+				err = writeTargetsJson(stdout, inv)
+			} else {
+				err = writeTargetJson(stdout, inv, inv.Args[0])
+			}
+			if err != nil {
+				errlog.Println("Error:", err)
+				return 1
+			}
+			return 0
+		}
 		if err := EnsureSdkIfRequired(inv); err != nil {
 			errlog.Println("Error:", err)
 			return 1
 		}
-		return mage.Invoke(inv.Invocation)
+		if inv.List {
+			// Keeps the targets offered by the completion up to date.
+			if _, err := ListTargets(inv); err != nil {
+				debug.Println("cannot list targets for completion:", err)
+			}
+		}
+		if inv.ForceTargets {
+			// Let uptodate.Target run regardless whether it is up to date.
+			if err := os.Setenv(uptodate.EnvForce, "true"); err != nil {
+				errlog.Println("Error:", err)
+				return 1
+			}
+		}
+		return invokeRedacted(inv, errlog)
 	default:
 		panic(fmt.Errorf("unknown command type: %v", cmd))
 	}
 }
 
+// invokeRedacted runs the magefile and replaces all secrets of the
+// environment (see values.RegisterSecretsFromEnv) and the ones the magefile
+// registers itself (see values.EnvRedacting) inside of its output by ***.
+//
+// To do so the output of the magefile has to pass this process, so it is
+// not attached to a terminal anymore. Therefore if stdout and stderr are
+// terminals and the environment does not contain any secrets the output is
+// not redacted at all; secrets the magefile registers itself are shown
+// then.
+func invokeRedacted(inv Invocation, errlog *log.Logger) int {
+	if err := values.RegisterSecretsFromEnv(); err != nil {
+		errlog.Println("Error:", err)
+		return 1
+	}
+	if len(values.Secrets()) == 0 && isTerminal(inv.Stdout) && isTerminal(inv.Stderr) {
+		// Keep stdout and stderr of the magefile attached to the terminal.
+		return invoke(inv.Invocation)
+	}
+	if err := values.RedactChildren(); err != nil {
+		errlog.Println("Error:", err)
+		return 1
+	}
+	stdout, stderr := values.NewRedactingWriter(inv.Stdout), values.NewRedactingWriter(inv.Stderr)
+	inv.Stdout, inv.Stderr = stdout, stderr
+	defer func() {
+		_ = stdout.Flush()
+		_ = stderr.Flush()
+	}()
+	return invoke(inv.Invocation)
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && mio.IsTerminal(f)
+}
+
 func EnsureSdkIfRequired(inv Invocation) error {
 	if !inv.EnsureSdk {
 		return nil
 	}
-	s, err := sdk.Discover()
+	s, err := ResolveSdk(inv)
 	if err != nil {
 		return err
 	}
@@ -146,18 +312,42 @@ func EnsureSdkIfRequired(inv Invocation) error {
 	return nil
 }
 
+// ResolveSdk discovers (and if required downloads) the golang SDK which
+// matches the given invocation.
+func ResolveSdk(inv Invocation) (sdk.Sdk, error) {
+	discoveries := sdk.DefaultDiscoveries
+	var predicates []sdk.Predicate
+	if inv.GoVersion != "" {
+		dd, err := sdk.NewDownloadDiscovery(inv.GoVersion)
+		if err != nil {
+			return sdk.Sdk{}, err
+		}
+		discoveries = []sdk.Discovery{sdk.DiscoveryFromPath(), sdk.DiscoveryFromGoroot(), dd}
+		predicates = append(predicates, sdk.IsVersion(dd.Version.String()))
+		if err := os.Setenv(sdk.EnvVersion, inv.GoVersion); err != nil {
+			return sdk.Sdk{}, err
+		}
+	}
+	return sdk.DiscoverUsing(discoveries, predicates...)
+}
+
 // Parse parses the given args and returns structured data.  If parse returns
 // flag.ErrHelp, the calling process should exit with code 0.
 func Parse(stderr, stdout io.Writer, args []string) (inv Invocation, cmd mage.Command, err error) {
 	inv.Stdout = stdout
 	fs := flag.FlagSet{}
 	fs.SetOutput(stdout)
+	inv.flags = &fs
 
 	// options flags
 
 	fs.BoolVar(&inv.Force, "f", false, "force recreation of compiled magefile")
+	fs.BoolVar(&inv.ForceTargets, "force-targets", false, "run targets of package uptodate even if they are up to date")
 	fs.BoolVar(&inv.Debug, "debug", mg.Debug(), "turn on debug messages")
 	fs.BoolVar(&inv.EnsureSdk, "ensuresdk", true, "will ensure a working golang SDK")
+	inv.Defines = map[string]string{}
+	fs.Var(defines(inv.Defines), "D", "sets the variable name to value (name=value); could be repeated")
+	fs.StringVar(&inv.GoVersion, "go", "", "will ensure a golang SDK of the given version")
 	fs.BoolVar(&inv.Verbose, "v", mg.Verbose(), "show verbose output when running mage targets")
 	fs.BoolVar(&inv.Help, "h", false, "show this help")
 	fs.DurationVar(&inv.Timeout, "t", 0, "timeout in duration parsable format (e.g. 5m30s)")
@@ -166,6 +356,14 @@ func Parse(stderr, stdout io.Writer, args []string) (inv Invocation, cmd mage.Co
 	fs.StringVar(&inv.GoCmd, "gocmd", mg.GoCmd(), "use the given go binary to compile the output")
 	fs.StringVar(&inv.GOOS, "goos", "", "set GOOS for binary produced with -compile")
 	fs.StringVar(&inv.GOARCH, "goarch", "", "set GOARCH for binary produced with -compile")
+	fs.StringVar(&inv.Format, "format", FormatText, "format of the output of -l, -h <target> and -version (text or json)")
+	fs.BoolVar(&inv.DryRun, "dry-run", false, "only show what -update would change")
+	fs.BoolVar(&inv.Check, "check", false, "only check if the wrapper created by -wrapper is up to date or verify the checksums of -checksum")
+	fs.StringVar(&inv.WrapperBaseUrl, "base-url", "", "base URL of the releases the wrapper created by -wrapper downloads mageplus from")
+	var wrapperFlavours string
+	fs.StringVar(&wrapperFlavours, "flavours", "", "comma separated flavours of the wrapper scripts created by -wrapper (sh, cmd and ps1)")
+	var algorithm string
+	fs.StringVar(&algorithm, "algorithm", "", "algorithm of the checksums of -checksum (sha256 or sha512)")
 
 	// commands below
 
@@ -176,10 +374,20 @@ func Parse(stderr, stdout io.Writer, args []string) (inv Invocation, cmd mage.Co
 	fs.BoolVar(&mageInit, "init", false, "create a starting template if no mage files exist")
 	var ensureWrapper bool
 	fs.BoolVar(&ensureWrapper, "wrapper", false, "ensures a wrapper with the version of this mageplus binary")
+	var selfUpdate bool
+	fs.BoolVar(&selfUpdate, "update", false, "updates mageplus and the wrapper to the latest or the given version")
+	var envEncryptCmd bool
+	fs.BoolVar(&envEncryptCmd, "env-encrypt", false, "encrypts the given dotenv file (default: .env.mage) into .env.mage.enc")
+	var envEditCmd bool
+	fs.BoolVar(&envEditCmd, "env-edit", false, "edits .env.mage.enc using $EDITOR")
+	var checksumCmd bool
+	fs.BoolVar(&checksumCmd, "checksum", false, "writes SHA256SUMS for the files matching the given patterns (default: *)")
 	var clean bool
 	fs.BoolVar(&clean, "clean", false, "clean out old generated binaries from CACHE_DIR")
 	var compileOutPath string
 	fs.StringVar(&compileOutPath, "compile", "", "output a static binary to the given path")
+	fs.StringVar(&inv.CompletionShell, "completion", "", "print the completion script for the given shell")
+	fs.StringVar(&inv.CompleteWord, "complete", "", "print the completion candidates for the given word (used by completion scripts)")
 
 	fs.Usage = func() {
 		_, _ = fmt.Fprint(stdout, `
@@ -188,23 +396,63 @@ mageplus [options] [target]
 MagePlus is a make-like command runner.  See https://github.com/echocat/mageplus for full docs.
 
 Commands:
+  -checksum [<pattern>...]
+             writes SHA256SUMS (or SHA512SUMS, see -algorithm) in coreutils
+             format for the files matching the given glob patterns (default: *);
+             with -check the files are verified against it instead
   -clean     clean out old generated binaries from CACHE_DIR
   -compile <string>
              output a static binary to the given path
+  -completion <string>
+             print the completion script for the given shell
+             (bash, zsh, fish or powershell)
+  -env-encrypt [<file>]
+             encrypts the given dotenv file (default: .env.mage) into .env.mage.enc
+             (key is taken from $MAGEPLUS_ENV_KEY, $MAGEPLUS_ENV_KEY_FILE or prompted)
+  -env-edit  edits .env.mage.enc using $VISUAL or $EDITOR
   -init      create a starting template if no mage files exist
   -wrapper   ensures a wrapper with the version of this mageplus binary
+             (retrieves the checksums of the binaries of this release)
   -l         list mage targets in this directory
+  -update [<version>]
+             updates mageplus and the wrapper to the latest or the given version
+             (releases are taken from $MAGEPLUS_RELEASES_URL if set)
   -h         show this help
   -version   show version info for the mageplus binary
 
 Options:
   -d <string> 
              run magefiles in the given directory (default ".")
+  -D <name>=<value>
+             sets the variable name to value, taking precedence over the
+             environment and dotenv files; could be repeated
+  -algorithm <string>
+             algorithm of the checksums of -checksum
+             (sha256 or sha512; default: sha256)
+  -base-url <string>
+             base URL of the releases (or a mirror of them) the wrapper created
+             by -wrapper downloads mageplus from
+  -check     only check if the wrapper created by -wrapper is up to date
+             (exits with 1 and shows the differences if not); with -checksum
+             verify the files against the existing checksums
   -debug     turn on debug messages
+  -dry-run   only show what -update would change
   -ensuresdk will ensure a working golang SDK (default: true)
   -h         show description of a target
   -f         force recreation of compiled magefile
+  -force-targets
+             run targets of package uptodate even if they are up to date
+             (same as MAGEPLUS_FORCE=true)
+  -flavours <string>
+             comma separated flavours of the wrapper scripts created by -wrapper
+             (sh, cmd and ps1; default: all; with -check: the existing ones)
+  -format <string>
+             format of the output of -l, -h <target> and -version
+             (text or json; default: text)
   -keep      keep intermediate mage files around after running
+  -go <string>
+             will ensure a golang SDK of the given version (default: $GO_VERSION or any available);
+             with -wrapper the version is pinned for the wrapper
   -gocmd <string>
 		     use the given go binary to compile the output (default: "go")
   -goos      sets the GOOS for the binary created by -compile (default: current OS)
@@ -225,14 +473,39 @@ Options:
 		return inv, cmd, flag.ErrHelp
 	}
 
+	complete := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "complete" {
+			complete = true
+		}
+	})
+
 	numCommands := 0
 	switch {
+	case complete:
+		numCommands++
+		cmd = Complete
+	case inv.CompletionShell != "":
+		numCommands++
+		cmd = Completion
 	case mageInit:
 		numCommands++
 		cmd = mage.Init
 	case ensureWrapper:
 		numCommands++
 		cmd = Wrapper
+	case selfUpdate:
+		numCommands++
+		cmd = Update
+	case envEncryptCmd:
+		numCommands++
+		cmd = EnvEncrypt
+	case envEditCmd:
+		numCommands++
+		cmd = EnvEdit
+	case checksumCmd:
+		numCommands++
+		cmd = Checksum
 	case compileOutPath != "":
 		numCommands++
 		cmd = mage.CompileStatic
@@ -246,7 +519,7 @@ Options:
 		cmd = mage.Clean
 		if fs.NArg() > 0 {
 			// Temporary dupe of below check until we refactor the other commands to use this check
-			return inv, cmd, errors.New("-h, -init, -wrapper, -clean, -compile and -version cannot be used simultaneously")
+			return inv, cmd, errors.New("-h, -init, -wrapper, -update, -env-encrypt, -env-edit, -checksum, -clean, -compile, -completion and -version cannot be used simultaneously")
 
 		}
 	}
@@ -262,7 +535,7 @@ Options:
 
 	if numCommands > 1 {
 		debug.Printf("%d commands defined", numCommands)
-		return inv, cmd, errors.New("-h, -init, -wrapper, -clean, -compile and -version cannot be used simultaneously")
+		return inv, cmd, errors.New("-h, -init, -wrapper, -update, -env-encrypt, -env-edit, -checksum, -clean, -compile, -completion and -version cannot be used simultaneously")
 	}
 
 	if cmd != mage.CompileStatic && (inv.GOARCH != "" || inv.GOOS != "") {
@@ -274,40 +547,92 @@ Options:
 		return inv, cmd, errors.New("-h can only show help for a single target")
 	}
 
-	if len(inv.Args) > 0 && cmd != mage.None {
+	if cmd == Update && len(inv.Args) > 1 {
+		return inv, cmd, errors.New("-update accepts at most one version")
+	}
+
+	if cmd == EnvEncrypt && len(inv.Args) > 1 {
+		return inv, cmd, errors.New("-env-encrypt accepts at most one file")
+	}
+
+	if cmd == Checksum && inv.Check && len(inv.Args) > 0 {
+		return inv, cmd, errors.New("-checksum -check does not accept patterns")
+	}
+
+	if len(inv.Args) > 0 && cmd != mage.None && cmd != Complete && cmd != Update && cmd != EnvEncrypt && cmd != Checksum {
 		return inv, cmd, fmt.Errorf("unexpected arguments to command: %q", inv.Args)
 	}
-	inv.HashFast = mg.HashFast()
-	return inv, cmd, err
-}
 
-func resolveDotEnvFiles() ([]string, error) {
-	var result []string
-	for _, candidate := range DotEnvFilesCandidates {
-		if exist, err := mio.FileExists(candidate); err != nil {
-			return nil, err
-		} else if exist {
-			result = append(result, candidate)
+	if inv.DryRun && cmd != Update {
+		return inv, cmd, errors.New("-dry-run only applies to -update")
+	}
+
+	if inv.Check && cmd != Wrapper && cmd != Checksum {
+		return inv, cmd, errors.New("-check only applies to -wrapper and -checksum")
+	}
+
+	if inv.WrapperBaseUrl != "" && cmd != Wrapper {
+		return inv, cmd, errors.New("-base-url only applies to -wrapper")
+	}
+
+	if wrapperFlavours != "" && cmd != Wrapper {
+		return inv, cmd, errors.New("-flavours only applies to -wrapper")
+	}
+	if wrapperFlavours != "" {
+		flavours, ferr := wrapper.ParseFlavours(wrapperFlavours)
+		if ferr != nil {
+			return inv, cmd, ferr
+		}
+		inv.WrapperFlavours = flavours
+	}
+
+	if algorithm != "" && cmd != Checksum {
+		return inv, cmd, errors.New("-algorithm only applies to -checksum")
+	}
+	inv.Algorithm = mio.Sha256
+	if algorithm != "" {
+		var aerr error
+		if inv.Algorithm, aerr = mio.ParseChecksumAlgorithm(algorithm); aerr != nil {
+			return inv, cmd, aerr
+		}
+	}
+
+	switch inv.Format {
+	case FormatText:
+	case FormatJson:
+		if cmd != mage.Version && !inv.List && !(inv.Help && len(inv.Args) == 1) {
+			return inv, cmd, errors.New("-format json only applies to -l, -h <target> and -version")
 		}
+	default:
+		return inv, cmd, fmt.Errorf("unsupported format %q; supported are: %s", inv.Format, strings.Join(Formats, ", "))
 	}
-	return result, nil
+	inv.HashFast = mg.HashFast()
+	return inv, cmd, err
 }
 
 func generateInit(dir string) error {
 	debug.Println("generating default magefile in", dir)
-	f, err := os.Create(filepath.Join(dir, initFile))
-	if err != nil {
+	if err := mio.WriteAtomic(filepath.Join(dir, initFile), 0644, func(w io.Writer) error {
+		return initOutput.Execute(w, nil)
+	}); err != nil {
 		return fmt.Errorf("could not create mage template: %v", err)
 	}
-	defer mio.CloseQuietly(f)
-
-	if err := initOutput.Execute(f, nil); err != nil {
-		return fmt.Errorf("can't execute magefile template: %v", err)
-	}
 
 	return nil
 }
 
+// cleanCacheDir removes the contents of the given cache directory while holding its
+// lock.
+func cleanCacheDir(cacheDir string) error {
+	lock, err := mio.Lock(cacheDir)
+	if err != nil {
+		return err
+	}
+	//noinspection GoUnhandledErrorResult
+	defer lock.Unlock()
+	return removeContents(cacheDir)
+}
+
 // removeContents removes all files but not any subdirectories in the given
 // directory.
 func removeContents(dir string) error {
@@ -0,0 +1,252 @@
+package mageplus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"github.com/magefile/mage/mage"
+	"github.com/magefile/mage/parse"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// (Aaaa)(Bbbb) -> aaaaBbbb
+	firstWordRx = regexp.MustCompile(`^([[:upper:]][^[:upper:]]+)([[:upper:]].*)$`)
+	// (AAAA)(Bbbb) -> aaaaBbbb
+	firstAbbrevRx = regexp.MustCompile(`^([[:upper:]]+)([[:upper:]][^[:upper:]].*)$`)
+)
+
+// Targets describes all targets of the magefiles of a directory.
+type Targets struct {
+	Description string   `json:"description,omitempty"`
+	Default     string   `json:"default,omitempty"`
+	Targets     []Target `json:"targets"`
+}
+
+// Target describes one target of a magefile. Parameters of targets are not
+// described, because mage does not support them yet.
+type Target struct {
+	Name        string   `json:"name"`
+	Namespace   string   `json:"namespace,omitempty"`
+	Package     string   `json:"package,omitempty"`
+	Synopsis    string   `json:"synopsis"`
+	Description string   `json:"description"`
+	Aliases     []string `json:"aliases"`
+	Default     bool     `json:"default"`
+}
+
+// Find returns the target with the given name or alias (case insensitive)
+// or nil if there is no such target.
+func (instance Targets) Find(name string) *Target {
+	for i, candidate := range instance.Targets {
+		if strings.EqualFold(candidate.Name, name) {
+			return &instance.Targets[i]
+		}
+		for _, alias := range candidate.Aliases {
+			if strings.EqualFold(alias, name) {
+				return &instance.Targets[i]
+			}
+		}
+	}
+	return nil
+}
+
+// Names returns the names of all targets.
+func (instance Targets) Names() []string {
+	result := make([]string, len(instance.Targets))
+	for i, target := range instance.Targets {
+		result[i] = target.Name
+	}
+	return result
+}
+
+// NamesAndAliases returns the sorted names and aliases of all targets.
+func (instance Targets) NamesAndAliases() []string {
+	result := instance.Names()
+	for _, target := range instance.Targets {
+		result = append(result, target.Aliases...)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ListTargetNames returns the names of all targets of the magefiles in the
+// directory of the given invocation - exactly like they are accepted by the
+// compiled magefile.
+func ListTargetNames(inv Invocation) ([]string, error) {
+	targets, err := ListTargets(inv)
+	if err != nil {
+		return nil, err
+	}
+	return targets.Names(), nil
+}
+
+// ListTargets returns all targets of the magefiles in the directory of the
+// given invocation. The result is cached next to the compiled magefile inside
+// of the CacheDir, so subsequent calls are cheap as long the magefiles are
+// unchanged.
+func ListTargets(inv Invocation) (Targets, error) {
+	files, err := mage.Magefiles(inv.Dir, inv.GOOS, inv.GOARCH, inv.GoCmd, ioutil.Discard, inv.Debug)
+	if err != nil {
+		return Targets{}, fmt.Errorf("cannot determine list of magefiles: %v", err)
+	}
+	if len(files) == 0 {
+		return Targets{}, nil
+	}
+	exe, err := mage.ExeName(inv.GoCmd, inv.CacheDir, files)
+	if err != nil {
+		return Targets{}, fmt.Errorf("cannot determine name of compiled magefile: %v", err)
+	}
+	cacheFile := strings.TrimSuffix(exe, ".exe") + ".targets.json"
+
+	if b, err := ioutil.ReadFile(cacheFile); err == nil {
+		var result Targets
+		if err = json.Unmarshal(b, &result); err == nil {
+			writeCompletionTargets(inv, b)
+			return result, nil
+		}
+		debug.Printf("ignoring broken cached targets in '%s': %v", cacheFile, err)
+	} else if !os.IsNotExist(err) {
+		return Targets{}, fmt.Errorf("cannot read cached targets from '%s': %v", cacheFile, err)
+	}
+
+	fileNames := make([]string, len(files))
+	for i, file := range files {
+		fileNames[i] = filepath.Base(file)
+	}
+	info, err := parse.PrimaryPackage(inv.GoCmd, inv.Dir, fileNames)
+	if err != nil {
+		return Targets{}, fmt.Errorf("cannot parse magefiles: %v", err)
+	}
+	result := targetsOf(info)
+
+	b, err := json.Marshal(result)
+	if err != nil {
+		return Targets{}, err
+	}
+	if err := os.MkdirAll(inv.CacheDir, 0700); err != nil {
+		return Targets{}, fmt.Errorf("cannot create cache directory '%s': %v", inv.CacheDir, err)
+	}
+	if err := ioutil.WriteFile(cacheFile, b, 0644); err != nil {
+		return Targets{}, fmt.Errorf("cannot cache targets in '%s': %v", cacheFile, err)
+	}
+	writeCompletionTargets(inv, b)
+
+	return result, nil
+}
+
+// CompletionTargets returns the targets of the directory of the given
+// invocation like they were cached by the last call of ListTargets. Other
+// than ListTargets it neither requires the golang SDK nor runs any go
+// command, so it could be used by the completion without the risk of any
+// download.
+func CompletionTargets(inv Invocation) (Targets, error) {
+	file, err := completionTargetsFile(inv)
+	if err != nil {
+		return Targets{}, err
+	}
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return Targets{}, nil
+	} else if err != nil {
+		return Targets{}, fmt.Errorf("cannot read cached targets from '%s': %v", file, err)
+	}
+	var result Targets
+	if err := json.Unmarshal(b, &result); err != nil {
+		return Targets{}, fmt.Errorf("cannot read cached targets from '%s': %v", file, err)
+	}
+	return result, nil
+}
+
+// completionTargetsFile is located by the directory of the magefiles instead
+// of by their content (like the cache of ListTargets), because determining
+// the magefiles requires go commands.
+func completionTargetsFile(inv Invocation) (string, error) {
+	dir, err := filepath.Abs(inv.Dir)
+	if err != nil {
+		return "", err
+	}
+	key := sha256.Sum256([]byte(dir))
+	return filepath.Join(inv.CacheDir, "completion-"+hex.EncodeToString(key[:8])+".json"), nil
+}
+
+func writeCompletionTargets(inv Invocation, b []byte) {
+	file, err := completionTargetsFile(inv)
+	if err == nil {
+		err = mio.WriteFileAtomic(file, b, 0644)
+	}
+	if err != nil {
+		debug.Printf("cannot cache targets for completion: %v", err)
+	}
+}
+
+func targetsOf(info *parse.PkgInfo) Targets {
+	aliases := map[*parse.Function][]string{}
+	for alias, f := range info.Aliases {
+		aliases[f] = append(aliases[f], alias)
+	}
+
+	result := Targets{
+		Description: info.Description,
+		Targets:     []Target{},
+	}
+	add := func(f *parse.Function) {
+		target := Target{
+			Name:        targetNameOf(f),
+			Namespace:   lowerFirstWord(f.Receiver),
+			Package:     f.ImportPath,
+			Synopsis:    f.Synopsis,
+			Description: f.Comment,
+			Aliases:     aliases[f],
+			Default:     f == info.DefaultFunc,
+		}
+		if target.Aliases == nil {
+			target.Aliases = []string{}
+		}
+		sort.Strings(target.Aliases)
+		if target.Default {
+			result.Default = target.Name
+		}
+		result.Targets = append(result.Targets, target)
+	}
+	for _, f := range info.Funcs {
+		add(f)
+	}
+	for _, imp := range info.Imports {
+		for _, f := range imp.Info.Funcs {
+			add(f)
+		}
+	}
+
+	sort.Slice(result.Targets, func(i, j int) bool {
+		return result.Targets[i].Name < result.Targets[j].Name
+	})
+	return result
+}
+
+// targetNameOf returns the name of the target how mage exposes it on the
+// command line.
+func targetNameOf(f *parse.Function) string {
+	parts := strings.Split(f.TargetName(), ":")
+	for i, part := range parts {
+		parts[i] = lowerFirstWord(part)
+	}
+	return strings.Join(parts, ":")
+}
+
+func lowerFirstWord(s string) string {
+	if match := firstWordRx.FindStringSubmatch(s); match != nil {
+		return strings.ToLower(match[1]) + match[2]
+	}
+	if match := firstAbbrevRx.FindStringSubmatch(s); match != nil {
+		return strings.ToLower(match[1]) + match[2]
+	}
+	return strings.ToLower(s)
+}
@@ -0,0 +1,137 @@
+package mageplus
+
+import (
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"github.com/echocat/mageplus/release"
+	"github.com/echocat/mageplus/wrapper"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// update replaces the current binary (or the copy of the binary in the cache
+// of the wrapper) with the requested release and regenerates the wrapper in
+// inv.Dir if present.
+func update(inv Invocation, out *log.Logger) error {
+	properties, _, err := wrapper.LoadProperties(inv.Dir)
+	if err != nil {
+		return err
+	}
+	baseUrl := release.BaseUrl(properties.ReleasesUrl)
+	var version string
+	if len(inv.Args) > 0 {
+		version = release.NormalizeVersion(inv.Args[0])
+	} else if v, err := release.LatestVersion(baseUrl); err != nil {
+		return fmt.Errorf("cannot resolve latest version of mageplus: %v", err)
+	} else {
+		version = v
+	}
+
+	unixScriptFile := filepath.Join(inv.Dir, "mageplusw")
+	wrapperExists, err := mio.FileExists(unixScriptFile)
+	if err != nil {
+		return err
+	}
+
+	if version == release.NormalizeVersion(gitTag) && !inv.Force {
+		out.Printf("mageplus is already at version %s", version)
+		if wrapperExists {
+			// The scripts or checksums of the wrapper could be outdated
+			// nevertheless.
+			return updateWrapperIfDrifted(inv, out, version)
+		}
+		return nil
+	}
+
+	r, err := release.Get(baseUrl, version)
+	if err != nil {
+		return err
+	}
+	target, err := updateTarget(inv.Dir, version)
+	if err != nil {
+		return err
+	}
+
+	if inv.DryRun {
+		out.Printf("Would download %s", r.ArtifactUrl(runtime.GOOS, runtime.GOARCH))
+		out.Printf("Would install mageplus %s to %s", version, target)
+		if wrapperExists {
+			out.Printf("Would regenerate the wrapper in %s with version %s", inv.Dir, version)
+		}
+		return nil
+	}
+
+	debug.Printf("downloading mageplus %s to %s", version, target)
+	if err := r.DownloadBinary(runtime.GOOS, runtime.GOARCH, target); err != nil {
+		return err
+	}
+	out.Printf("mageplus %s installed to %s", version, target)
+
+	if wrapperExists {
+		options, err := updateWrapperOptions(inv.Dir, version)
+		if err != nil {
+			return err
+		}
+		if err := wrapper.WriteWith(inv.Dir, options); err != nil {
+			return err
+		}
+		out.Println("mageplusw", "updated to", version)
+	}
+	return nil
+}
+
+// updateWrapperIfDrifted regenerates the wrapper in inv.Dir if it differs
+// from what the given version would generate.
+func updateWrapperIfDrifted(inv Invocation, out *log.Logger, version string) error {
+	options, err := updateWrapperOptions(inv.Dir, version)
+	if err != nil {
+		return err
+	}
+	drifts, err := wrapper.Check(inv.Dir, options)
+	if err != nil {
+		return err
+	}
+	if len(drifts) == 0 {
+		return nil
+	}
+	if inv.DryRun {
+		out.Printf("Would regenerate the wrapper in %s with version %s", inv.Dir, version)
+		return nil
+	}
+	if err := wrapper.WriteWith(inv.Dir, options); err != nil {
+		return err
+	}
+	out.Println("mageplusw", "regenerated with", version)
+	return nil
+}
+
+func updateWrapperOptions(dir, version string) (wrapper.Options, error) {
+	flavours, err := wrapper.ExistingFlavours(dir)
+	if err != nil {
+		return wrapper.Options{}, err
+	}
+	return wrapper.Options{Version: version, Flavours: flavours, FetchChecksums: true}, nil
+}
+
+// updateTarget returns the file which should be replaced by the new binary.
+// If this binary was started from the cache of the wrapper the new version is
+// placed next to it, because the wrapper expects the version in the file name.
+func updateTarget(dir, version string) (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine location of the current binary: %v", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	cacheDir, err := wrapper.BinariesCacheDir(dir)
+	if err != nil {
+		return "", err
+	}
+	if filepath.Dir(exe) == cacheDir {
+		return filepath.Join(cacheDir, wrapper.CachedBinaryName(version, runtime.GOOS, runtime.GOARCH)), nil
+	}
+	return exe, nil
+}
@@ -0,0 +1,258 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/echocat/mageplus/http"
+	mio "github.com/echocat/mageplus/io"
+	"github.com/mholt/archiver/v3"
+	"io"
+	"io/ioutil"
+	gohttp "net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	EnvBaseUrl        = "MAGEPLUS_RELEASES_URL"
+	EnvWrapperBaseUrl = "MAGEPLUSW_BASE_URL"
+	EnvToken          = "MAGEPLUSW_TOKEN"
+	EnvUsername       = "MAGEPLUSW_USERNAME"
+	EnvPassword       = "MAGEPLUSW_PASSWORD"
+)
+
+var (
+	DefaultBaseUrl = "https://github.com/echocat/mageplus/releases"
+
+	ErrNoChecksum = errors.New("no checksum")
+
+	artifactNamePattern = regexp.MustCompile(`^mageplus_([^_]+)_([^_]+-[^_.]+)\.(tar\.gz|zip)$`)
+
+	osNames = map[string]string{
+		"darwin":    "macOS",
+		"linux":     "Linux",
+		"windows":   "Windows",
+		"openbsd":   "OpenBSD",
+		"netbsd":    "NetBSD",
+		"freebsd":   "FreeBSD",
+		"dragonfly": "DragonFlyBSD",
+	}
+	archNames = map[string]string{
+		"amd64": "64bit",
+		"386":   "32bit",
+		"arm":   "ARM",
+		"arm64": "ARM64",
+	}
+)
+
+// Release represents one published release of mageplus.
+type Release struct {
+	Version   string
+	BaseUrl   string
+	Checksums map[string]string // Checksums (SHA-256) by artifact name
+}
+
+// BaseUrl returns the base URL of the releases. The environment variables
+// MAGEPLUS_RELEASES_URL and MAGEPLUSW_BASE_URL (in this order) take
+// precedence over def, which is meant to be the configured URL. URLs given
+// explicitly (like by -base-url) should be used as is instead. If def is
+// empty DefaultBaseUrl is used.
+func BaseUrl(def string) string {
+	for _, env := range []string{EnvBaseUrl, EnvWrapperBaseUrl} {
+		if v, ok := os.LookupEnv(env); ok && v != "" {
+			return strings.TrimSuffix(v, "/")
+		}
+	}
+	if def != "" {
+		return strings.TrimSuffix(def, "/")
+	}
+	return DefaultBaseUrl
+}
+
+// authPlugins returns the authorization for the requests to the releases
+// which is configured by either MAGEPLUSW_TOKEN (bearer) or MAGEPLUSW_USERNAME
+// and MAGEPLUSW_PASSWORD (basic).
+func authPlugins() []http.Plugin {
+	if v, ok := os.LookupEnv(EnvToken); ok && v != "" {
+		return []http.Plugin{http.BearerAuth(v)}
+	}
+	if v, ok := os.LookupEnv(EnvUsername); ok && v != "" {
+		return []http.Plugin{http.BasicAuth(v, os.Getenv(EnvPassword))}
+	}
+	return nil
+}
+
+// LatestVersion resolves the version of the latest release by following
+// the redirect of <baseUrl>/latest to <baseUrl>/tag/v<version>.
+func LatestVersion(baseUrl string) (string, error) {
+	var result string
+	if err := http.Execute(baseUrl+"/latest", append(authPlugins(), http.EvalResponseFunc(func(_ context.Context, resp *gohttp.Response, _ *gohttp.Request) error {
+		location := resp.Request.URL.Path
+		i := strings.LastIndex(location, "/tag/")
+		if i < 0 {
+			return fmt.Errorf("cannot resolve version of latest release from '%s'", resp.Request.URL)
+		}
+		result = NormalizeVersion(location[i+5:])
+		return nil
+	}))...); err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+// Get retrieves the metadata of the release of the given version.
+func Get(baseUrl, version string) (Release, error) {
+	result := Release{
+		Version:   NormalizeVersion(version),
+		BaseUrl:   baseUrl,
+		Checksums: map[string]string{},
+	}
+	if err := http.Execute(result.ChecksumsUrl(), append(authPlugins(), http.EvalBody(func(reader io.Reader) (err error) {
+		result.Checksums, err = mio.ParseChecksums(reader)
+		return
+	}))...); err != nil {
+		return Release{}, fmt.Errorf("cannot retrieve release %s: %v", result.Version, err)
+	}
+	return result, nil
+}
+
+// NormalizeVersion returns the version without the leading "v".
+func NormalizeVersion(version string) string {
+	return strings.TrimPrefix(version, "v")
+}
+
+func OsName(goos string) string {
+	if v, ok := osNames[goos]; ok {
+		return v
+	}
+	return goos
+}
+
+func ArchName(goarch string) string {
+	if v, ok := archNames[goarch]; ok {
+		return v
+	}
+	return goarch
+}
+
+func (instance Release) ChecksumsUrl() string {
+	return fmt.Sprintf("%s/download/v%s/mageplus_%s_checksums.txt", instance.BaseUrl, instance.Version, instance.Version)
+}
+
+func (instance Release) ArtifactName(goos, goarch string) string {
+	ext := ".tar.gz"
+	if goos == "windows" {
+		ext = ".zip"
+	}
+	return fmt.Sprintf("mageplus_%s_%s-%s%s", instance.Version, OsName(goos), ArchName(goarch), ext)
+}
+
+func (instance Release) ArtifactUrl(goos, goarch string) string {
+	return fmt.Sprintf("%s/download/v%s/%s", instance.BaseUrl, instance.Version, instance.ArtifactName(goos, goarch))
+}
+
+// Checksum returns the SHA-256 of the artifact for the given platform.
+func (instance Release) Checksum(goos, goarch string) (string, error) {
+	if v, ok := instance.Checksums[instance.ArtifactName(goos, goarch)]; ok {
+		return v, nil
+	}
+	return "", ErrNoChecksum
+}
+
+// ChecksumsByPlatform returns the SHA-256 of the artifacts of this release by
+// platform in the format <os>-<arch> (e.g. Linux-64bit) like used by the
+// wrapper scripts.
+func (instance Release) ChecksumsByPlatform() map[string]string {
+	result := map[string]string{}
+	for name, checksum := range instance.Checksums {
+		if match := artifactNamePattern.FindStringSubmatch(name); match != nil && match[1] == instance.Version {
+			result[match[2]] = checksum
+		}
+	}
+	return result
+}
+
+// DownloadBinary downloads the artifact for the given platform, verifies its
+// checksum and extracts the contained mageplus binary to target. The target
+// is replaced atomically.
+func (instance Release) DownloadBinary(goos, goarch, target string) error {
+	expected, err := instance.Checksum(goos, goarch)
+	if err == ErrNoChecksum {
+		return fmt.Errorf("release %s does not contain a checksum for %s", instance.Version, instance.ArtifactName(goos, goarch))
+	} else if err != nil {
+		return err
+	}
+
+	// The target is usually inside of the shared binaries cache.
+	lock, err := mio.Lock(target)
+	if err != nil {
+		return err
+	}
+	//noinspection GoUnhandledErrorResult
+	defer lock.Unlock()
+
+	url := instance.ArtifactUrl(goos, goarch)
+	return http.Execute(url, append(authPlugins(), http.WriteToTemporaryFile("", instance.ArtifactName(goos, goarch), func(f *os.File) error {
+		if err := mio.VerifyChecksum(f.Name(), expected); errors.Is(err, mio.ErrChecksumMismatch) {
+			return fmt.Errorf("%v (downloaded from '%s')", err, url)
+		} else if err != nil {
+			return err
+		}
+		return instance.extractBinary(goos, f.Name(), target)
+	}))...)
+}
+
+func (instance Release) extractBinary(goos, archive, target string) error {
+	var walker archiver.Walker = archiver.NewTarGz()
+	binaryName := "mageplus"
+	if goos == "windows" {
+		walker = archiver.NewZip()
+		binaryName += ".exe"
+	}
+
+	found := false
+	if err := walker.Walk(archive, func(candidate archiver.File) error {
+		if candidate.IsDir() || candidate.Name() != binaryName {
+			return nil
+		}
+		found = true
+		if err := writeExecutable(target, candidate); err != nil {
+			return err
+		}
+		return archiver.ErrStopWalk
+	}); err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("artifact of release %s does not contain %s", instance.Version, binaryName)
+	}
+	return nil
+}
+
+func writeExecutable(target string, from io.Reader) error {
+	dir := filepath.Dir(target)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("cannot create directory '%s': %v", dir, err)
+	}
+	f, err := ioutil.TempFile(dir, filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot create temporary file for '%s': %v", target, err)
+	}
+	//noinspection GoUnhandledErrorResult
+	defer os.Remove(f.Name())
+	defer mio.CloseQuietly(f)
+
+	if _, err := io.Copy(f, from); err != nil {
+		return fmt.Errorf("cannot write '%s': %v", f.Name(), err)
+	}
+	if err := f.Chmod(0755); err != nil {
+		return fmt.Errorf("cannot make '%s' executable: %v", f.Name(), err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("cannot write '%s': %v", f.Name(), err)
+	}
+	return replaceFile(f.Name(), target)
+}
@@ -0,0 +1,9 @@
+//+build !windows
+
+package release
+
+import "os"
+
+func replaceFile(source, target string) error {
+	return os.Rename(source, target)
+}
@@ -0,0 +1,20 @@
+// +build windows
+
+package release
+
+import "os"
+
+// replaceFile moves the target out of the way before it is replaced by source
+// because Windows does not allow to overwrite the binary of a running process.
+func replaceFile(source, target string) error {
+	old := target + ".old"
+	_ = os.Remove(old)
+	if err := os.Rename(target, old); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Rename(source, target); err != nil {
+		_ = os.Rename(old, target)
+		return err
+	}
+	return nil
+}
@@ -9,6 +9,7 @@ import (
 	mio "github.com/echocat/mageplus/io"
 	"github.com/mholt/archiver/v3"
 	"io"
+	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
@@ -32,7 +33,7 @@ type DownloadDiscovery struct {
 func NewDownloadDiscovery(version string) (*DownloadDiscovery, error) {
 	parsedVersion, err := semver.ParseTolerant(version)
 	if err != nil {
-		return nil, nil
+		return nil, fmt.Errorf("illegal golang SDK version '%s': %v", version, err)
 	}
 	return &DownloadDiscovery{
 		Version: parsedVersion,
@@ -62,6 +63,14 @@ func (instance DownloadDiscovery) Discover() ([]Sdk, error) {
 		return nil, err
 	}
 
+	// Other processes could install the same SDK at the same time, so it is
+	// only validated while holding the lock.
+	lock, err := mio.Lock(candidate.Root)
+	if err != nil {
+		return nil, err
+	}
+	//noinspection GoUnhandledErrorResult
+	defer lock.Unlock()
 	if err := candidate.Validate(); err == nil {
 		return []Sdk{candidate}, nil
 	} else if err == ErrSdkDifferent {
@@ -74,11 +83,18 @@ func (instance DownloadDiscovery) Discover() ([]Sdk, error) {
 	if err != nil {
 		return nil, err
 	}
+	checksum, err := instance.checksum(downloadUrl)
+	if err != nil {
+		return nil, err
+	}
 	infoLog.Printf("Downloading Golang SDK from %s...", downloadUrl)
 
 	if err := http.Execute(downloadUrl,
 		http.WriteToTemporaryFile("", instance.String(), func(input *os.File) error {
-			return instance.extract(input.Name(), candidate)
+			if err := mio.VerifyChecksum(input.Name(), checksum); err != nil {
+				return fmt.Errorf("cannot verify '%s': %v", downloadUrl, err)
+			}
+			return instance.extract(input.Name(), candidate.Root)
 		}),
 	); err != nil {
 		return nil, err
@@ -87,13 +103,58 @@ func (instance DownloadDiscovery) Discover() ([]Sdk, error) {
 	return []Sdk{candidate}, nil
 }
 
-func (instance DownloadDiscovery) extract(input string, to Sdk) error {
-	return instance.walker().Walk(input, func(candidate archiver.File) error {
-		return instance.extractFile(candidate, to)
-	})
+// checksum retrieves the SHA-256 of the archive which is published next to it
+// (<downloadUrl>.sha256).
+func (instance DownloadDiscovery) checksum(downloadUrl string) (result string, err error) {
+	if err := http.Execute(downloadUrl+".sha256", http.EvalBody(func(reader io.Reader) error {
+		b, err := ioutil.ReadAll(reader)
+		if err != nil {
+			return err
+		}
+		// Either just the checksum or in the format of sha256sum.
+		if fields := strings.Fields(string(b)); len(fields) > 0 {
+			result = fields[0]
+		}
+		return nil
+	})); err != nil {
+		return "", fmt.Errorf("cannot retrieve checksum of '%s': %v", downloadUrl, err)
+	}
+	if _, err := mio.ChecksumAlgorithmOf(result); err != nil {
+		return "", fmt.Errorf("cannot retrieve checksum of '%s': %v", downloadUrl, err)
+	}
+	return result, nil
 }
 
-func (instance DownloadDiscovery) extractFile(candidate archiver.File, to Sdk) error {
+// extract extracts the archive into a temporary directory next to root,
+// which replaces root afterwards. So root either contains a complete SDK or
+// none at all.
+func (instance DownloadDiscovery) extract(input string, root string) error {
+	if err := os.MkdirAll(filepath.Dir(root), 0755); err != nil {
+		return fmt.Errorf("cannot create parent of '%s': %v", root, err)
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(root), "."+filepath.Base(root)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("cannot extract SDK to '%s': %v", root, err)
+	}
+	//noinspection GoUnhandledErrorResult
+	defer os.RemoveAll(tmp)
+
+	if err := instance.walker().Walk(input, func(candidate archiver.File) error {
+		return instance.extractFile(candidate, tmp)
+	}); err != nil {
+		return err
+	}
+	// Remains of a broken installation.
+	if err := os.RemoveAll(root); err != nil {
+		return fmt.Errorf("cannot remove '%s': %v", root, err)
+	}
+	if err := os.Rename(tmp, root); err != nil {
+		return fmt.Errorf("cannot extract SDK to '%s': %v", root, err)
+	}
+	return nil
+}
+
+func (instance DownloadDiscovery) extractFile(candidate archiver.File, root string) error {
 	var name string
 	if h, ok := candidate.Header.(zip.FileHeader); ok {
 		name = h.Name
@@ -112,7 +173,7 @@ func (instance DownloadDiscovery) extractFile(candidate archiver.File, to Sdk) e
 	if name == "" {
 		return nil
 	}
-	target := filepath.Join(to.Root, name)
+	target := filepath.Join(root, name)
 	if candidate.IsDir() {
 		if err := os.MkdirAll(target, candidate.Mode()); err != nil {
 			return fmt.Errorf("cannot extract '%s': %v", name, err)
@@ -124,13 +185,14 @@ func (instance DownloadDiscovery) extractFile(candidate archiver.File, to Sdk) e
 		return fmt.Errorf("cannot create parent of '%s': %v", name, err)
 	}
 
-	w, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, candidate.Mode())
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, candidate.Mode())
 	if err != nil {
 		return fmt.Errorf("cannot extract '%s': %v", name, err)
 	}
-	defer mio.CloseQuietly(w)
-
-	_, err = io.Copy(w, candidate)
+	_, err = io.Copy(f, candidate)
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
 	if err != nil {
 		return fmt.Errorf("cannot extract '%s': %v", name, err)
 	}
@@ -0,0 +1,76 @@
+package sdk
+
+import (
+	"fmt"
+	"github.com/blang/semver"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// KnownVersions returns the versions of golang SDKs which are available
+// without any download: The ones installed by DownloadDiscovery for the
+// current OS and architecture, the ones found in PATH and GOROOT as well as
+// DefaultVersion and EnvVersion. The result is sorted by version.
+func KnownVersions() ([]string, error) {
+	byVersion := map[string]semver.Version{}
+	add := func(plain string) {
+		if v, err := semver.ParseTolerant(plain); err == nil {
+			byVersion[DownloadDiscovery{Version: v}.VersionString()] = v
+		}
+	}
+
+	add(DefaultVersion)
+	if v, ok := os.LookupEnv(EnvVersion); ok {
+		add(v)
+	}
+	installed, err := InstalledVersions()
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range installed {
+		add(v)
+	}
+	for _, eval := range []func() (Sdk, error){EvalFromPath, EvalFromGoroot} {
+		if sdk, err := eval(); err == nil && sdk.GoBinary != "" {
+			add(sdk.Version.String())
+		}
+	}
+
+	result := make([]string, 0, len(byVersion))
+	for v := range byVersion {
+		result = append(result, v)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return byVersion[result[i]].LT(byVersion[result[j]])
+	})
+	return result, nil
+}
+
+// InstalledVersions returns the versions of all golang SDKs which were
+// installed by DownloadDiscovery for the current OS and architecture.
+func InstalledVersions() ([]string, error) {
+	gopath, err := DownloadDiscovery{}.Gopath()
+	if err != nil {
+		return nil, fmt.Errorf("cannot determine sdk target directory: %v", err)
+	}
+	dir := filepath.Join(gopath, "pkg", "sdk")
+	fis, err := ioutil.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot list installed SDKs in '%s': %v", dir, err)
+	}
+	suffix := "." + runtime.GOOS + "-" + runtime.GOARCH
+	var result []string
+	for _, fi := range fis {
+		// Hidden directories are temporary ones.
+		if name := fi.Name(); fi.IsDir() && !strings.HasPrefix(name, ".") && strings.HasSuffix(name, suffix) {
+			result = append(result, strings.TrimSuffix(name, suffix))
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,11 @@
+package uptodate
+
+import (
+	"log"
+	"os"
+)
+
+var (
+	errorLog = log.New(os.Stderr, "", 0)
+	infoLog  = log.New(os.Stderr, "", 0)
+)
@@ -0,0 +1,270 @@
+package uptodate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"github.com/magefile/mage/mg"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvForce could be set to true to run all targets regardless whether they
+// are up to date. mageplus sets it if called with -force-targets.
+const EnvForce = "MAGEPLUS_FORCE"
+
+// Target describes the inputs and outputs of some work inside of a mage
+// target. Other than target.Path of mage, which compares modification times,
+// a Target is up to date if the content of its inputs did not change since
+// its last successful run. This is also true after a git checkout or a
+// restore of a CI cache.
+//
+//	func Build() error {
+//		return uptodate.Target{
+//			Name:    "build",
+//			Inputs:  []string{"go.mod", "go.sum", "**/*.go"},
+//			Outputs: []string{"dist/app"},
+//		}.Run(func() error {
+//			return sh.Run("go", "build", "-o", "dist/app", ".")
+//		})
+//	}
+type Target struct {
+	Name     string   // Identifies the state of the target inside of the cache directory of mage
+	Dir      string   // Base directory of Inputs, Excludes and Outputs; empty means the working directory
+	Inputs   []string // Glob patterns (see io.MatchGlob) of the input files
+	Excludes []string // Glob patterns of files which are not inputs even if they match Inputs
+	Outputs  []string // Files or directories which have to exist for the target to be up to date
+}
+
+type state struct {
+	Name string `json:"name"`
+	Dir  string `json:"dir"`
+	Hash string `json:"hash"`
+}
+
+// Run runs f if the target is not up to date (see UpToDate) and records the
+// state of the inputs afterwards if f succeeded.
+func (instance Target) Run(f func() error) error {
+	hash, err := instance.Hash()
+	if err != nil {
+		return err
+	}
+	if ok, err := instance.upToDate(hash); err != nil {
+		return err
+	} else if ok {
+		if mg.Verbose() {
+			infoLog.Printf("%s is up to date", instance.Name)
+		}
+		return nil
+	}
+	if err := f(); err != nil {
+		return err
+	}
+	return instance.record(hash)
+}
+
+// MustRun is like Run but exits the process on errors.
+func (instance Target) MustRun(f func() error) {
+	if err := instance.Run(f); err != nil {
+		errorLog.Fatalln("Error:", err)
+	}
+}
+
+// UpToDate returns true if all outputs exist and the inputs are the same as
+// the last time the target was recorded. It is always false if EnvForce is
+// set to true.
+func (instance Target) UpToDate() (bool, error) {
+	hash, err := instance.Hash()
+	if err != nil {
+		return false, err
+	}
+	return instance.upToDate(hash)
+}
+
+// Record stores the current state of the inputs, so the target is up to date
+// until they change.
+func (instance Target) Record() error {
+	hash, err := instance.Hash()
+	if err != nil {
+		return err
+	}
+	return instance.record(hash)
+}
+
+// Hash returns the hash of the content, names and executable bits of all
+// input files together with the declaration of the target itself.
+func (instance Target) Hash() (string, error) {
+	files, err := instance.inputFiles()
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "inputs=%q\nexcludes=%q\noutputs=%q\n", instance.Inputs, instance.Excludes, instance.Outputs)
+	for _, file := range files {
+		if err := hashFile(h, instance.dir(), file); err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (instance Target) upToDate(hash string) (bool, error) {
+	if v, err := strconv.ParseBool(os.Getenv(EnvForce)); err == nil && v {
+		return false, nil
+	}
+	for _, output := range instance.Outputs {
+		if ok, err := mio.Exists(filepath.Join(instance.dir(), filepath.FromSlash(output))); err != nil || !ok {
+			return false, err
+		}
+	}
+	file, err := instance.stateFile()
+	if err != nil {
+		return false, err
+	}
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, fmt.Errorf("cannot read state of target '%s' from '%s': %v", instance.Name, file, err)
+	}
+	var s state
+	if err := json.Unmarshal(b, &s); err != nil {
+		// Broken states are just outdated.
+		return false, nil
+	}
+	return s.Hash == hash, nil
+}
+
+func (instance Target) record(hash string) error {
+	file, err := instance.stateFile()
+	if err != nil {
+		return err
+	}
+	dir, err := filepath.Abs(instance.dir())
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(state{Name: instance.Name, Dir: dir, Hash: hash})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return fmt.Errorf("cannot create cache directory '%s': %v", filepath.Dir(file), err)
+	}
+	if err := mio.WriteFileAtomic(file, b, 0644); err != nil {
+		return fmt.Errorf("cannot record state of target '%s': %v", instance.Name, err)
+	}
+	return nil
+}
+
+// stateFile is located directly inside of the cache directory of mage, so it
+// is removed by -clean together with the compiled magefiles.
+func (instance Target) stateFile() (string, error) {
+	if instance.Name == "" {
+		return "", fmt.Errorf("target without name: %+v", instance)
+	}
+	dir, err := filepath.Abs(instance.dir())
+	if err != nil {
+		return "", err
+	}
+	key := sha256.Sum256([]byte(dir + "\x00" + instance.Name))
+	return filepath.Join(mg.CacheDir(), "target-"+hex.EncodeToString(key[:8])+".json"), nil
+}
+
+func (instance Target) dir() string {
+	if instance.Dir == "" {
+		return "."
+	}
+	return instance.Dir
+}
+
+// inputFiles returns the sorted, slash separated names of all files matching
+// Inputs. Only the static prefixes of the patterns are walked.
+func (instance Target) inputFiles() ([]string, error) {
+	found := map[string]bool{}
+	for _, pattern := range instance.Inputs {
+		root := filepath.Join(instance.dir(), filepath.FromSlash(staticPrefix(pattern)))
+		if ok, err := mio.Exists(root); err != nil {
+			return nil, err
+		} else if !ok {
+			continue
+		}
+		if err := filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return fmt.Errorf("cannot read inputs of target '%s': %v", instance.Name, err)
+			}
+			rel, err := filepath.Rel(instance.dir(), path)
+			if err != nil {
+				return err
+			}
+			name := filepath.ToSlash(rel)
+			if excluded, err := mio.MatchAnyGlob(instance.Excludes, name); err != nil {
+				return err
+			} else if excluded && fi.IsDir() {
+				return filepath.SkipDir
+			} else if excluded || fi.IsDir() {
+				return nil
+			}
+			if ok, err := mio.MatchGlob(pattern, name); err != nil {
+				return err
+			} else if ok {
+				found[name] = true
+			}
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+	}
+	result := make([]string, 0, len(found))
+	for name := range found {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+func hashFile(h io.Writer, dir, name string) error {
+	file := filepath.Join(dir, filepath.FromSlash(name))
+	fi, err := os.Lstat(file)
+	if err != nil {
+		return fmt.Errorf("cannot hash '%s': %v", file, err)
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(file)
+		if err != nil {
+			return fmt.Errorf("cannot hash '%s': %v", file, err)
+		}
+		_, _ = fmt.Fprintf(h, "link %q %q\n", name, link)
+		return nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf("cannot hash '%s': %v", file, err)
+	}
+	defer mio.CloseQuietly(f)
+	content := sha256.New()
+	if _, err := io.Copy(content, f); err != nil {
+		return fmt.Errorf("cannot hash '%s': %v", file, err)
+	}
+	_, _ = fmt.Fprintf(h, "file %q %t %x\n", name, fi.Mode()&0111 != 0, content.Sum(nil))
+	return nil
+}
+
+// staticPrefix returns the leading segments of the pattern which do not
+// contain any wildcards, e.g. "src" of "src/**/*.go".
+func staticPrefix(pattern string) string {
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		if strings.ContainsAny(segment, `*?[\`) {
+			return strings.Join(segments[:i], "/")
+		}
+	}
+	return pattern
+}
@@ -0,0 +1,283 @@
+package values
+
+import (
+	"github.com/echocat/mageplus/git"
+	"os"
+	"strings"
+)
+
+// CIProvider identifies the CI system a build is running in.
+type CIProvider string
+
+const (
+	CILocal     CIProvider = "local"
+	CIGitLab    CIProvider = "gitlab"
+	CIGitHub    CIProvider = "github"
+	CIJenkins   CIProvider = "jenkins"
+	CIAzure     CIProvider = "azure"
+	CIBitbucket CIProvider = "bitbucket"
+)
+
+// BuildContext describes the build which is currently running, independent
+// of the CI system which runs it.
+type BuildContext struct {
+	Provider     CIProvider
+	Branch       string        // Branch which is built; for merge requests the source branch; empty if unknown (e.g. tags)
+	Tag          string        // Tag which is built; empty if not a tag build
+	Commit       string        // Full hash of the built commit
+	BuildNumber  string        // Number of the pipeline/build inside of the CI system; empty if local
+	MergeRequest *MergeRequest // Merge (or pull) request which is built; nil if none
+	JobUrl       string        // URL of the job inside of the CI system; empty if unknown
+}
+
+// MergeRequest describes the merge (or pull) request a build is running for.
+type MergeRequest struct {
+	Id           string
+	SourceBranch string
+	TargetBranch string
+	Url          string // Empty if unknown
+}
+
+// IsCI returns true if the build is running inside of a CI system.
+func (instance BuildContext) IsCI() bool {
+	return instance.Provider != CILocal
+}
+
+// CI detects the CI system the current process is running in and returns
+// the context of the build. Outside of a supported CI system the context is
+// resolved using the git repository of the current working directory.
+//
+// Fields the CI system leaves empty (like the commit of pull request builds
+// on Azure without BUILD_SOURCEVERSION) are resolved using git, if possible.
+func CI() (BuildContext, error) {
+	var result BuildContext
+	switch ciProvider() {
+	case CIGitLab:
+		result = gitLabBuildContext()
+	case CIGitHub:
+		result = gitHubBuildContext()
+	case CIJenkins:
+		result = jenkinsBuildContext()
+	case CIAzure:
+		result = azureBuildContext()
+	case CIBitbucket:
+		result = bitbucketBuildContext()
+	default:
+		return localBuildContext()
+	}
+	completeFromGit(&result)
+	return result, nil
+}
+
+// ciProvider detects the CI system without resolving the build context.
+func ciProvider() CIProvider {
+	switch {
+	case os.Getenv("GITLAB_CI") != "":
+		return CIGitLab
+	case os.Getenv("GITHUB_ACTIONS") != "":
+		return CIGitHub
+	case os.Getenv("JENKINS_URL") != "":
+		return CIJenkins
+	case os.Getenv("TF_BUILD") != "":
+		return CIAzure
+	case os.Getenv("BITBUCKET_BUILD_NUMBER") != "":
+		return CIBitbucket
+	default:
+		return CILocal
+	}
+}
+
+// MustCI is like CI but exits the process on errors.
+func MustCI() BuildContext {
+	v, err := CI()
+	if err != nil {
+		errorLog.Fatalln("Error:", err)
+	}
+	return v
+}
+
+func gitLabBuildContext() BuildContext {
+	result := BuildContext{
+		Provider:    CIGitLab,
+		Branch:      os.Getenv("CI_COMMIT_BRANCH"),
+		Tag:         os.Getenv("CI_COMMIT_TAG"),
+		Commit:      os.Getenv("CI_COMMIT_SHA"),
+		BuildNumber: firstEnv("CI_PIPELINE_IID", "CI_PIPELINE_ID"),
+		JobUrl:      os.Getenv("CI_JOB_URL"),
+	}
+	if id := os.Getenv("CI_MERGE_REQUEST_IID"); id != "" {
+		result.MergeRequest = &MergeRequest{
+			Id:           id,
+			SourceBranch: os.Getenv("CI_MERGE_REQUEST_SOURCE_BRANCH_NAME"),
+			TargetBranch: os.Getenv("CI_MERGE_REQUEST_TARGET_BRANCH_NAME"),
+		}
+		if projectUrl := os.Getenv("CI_MERGE_REQUEST_PROJECT_URL"); projectUrl != "" {
+			result.MergeRequest.Url = projectUrl + "/-/merge_requests/" + id
+		}
+		if result.Branch == "" {
+			result.Branch = result.MergeRequest.SourceBranch
+		}
+	}
+	return result
+}
+
+func gitHubBuildContext() BuildContext {
+	repositoryUrl := os.Getenv("GITHUB_SERVER_URL") + "/" + os.Getenv("GITHUB_REPOSITORY")
+	result := BuildContext{
+		Provider:    CIGitHub,
+		Commit:      os.Getenv("GITHUB_SHA"),
+		BuildNumber: os.Getenv("GITHUB_RUN_NUMBER"),
+		JobUrl:      repositoryUrl + "/actions/runs/" + os.Getenv("GITHUB_RUN_ID"),
+	}
+	ref := os.Getenv("GITHUB_REF")
+	switch {
+	case strings.HasPrefix(ref, "refs/heads/"):
+		result.Branch = strings.TrimPrefix(ref, "refs/heads/")
+	case strings.HasPrefix(ref, "refs/tags/"):
+		result.Tag = strings.TrimPrefix(ref, "refs/tags/")
+	case strings.HasPrefix(ref, "refs/pull/"):
+		id := strings.SplitN(strings.TrimPrefix(ref, "refs/pull/"), "/", 2)[0]
+		result.Branch = os.Getenv("GITHUB_HEAD_REF")
+		result.MergeRequest = &MergeRequest{
+			Id:           id,
+			SourceBranch: os.Getenv("GITHUB_HEAD_REF"),
+			TargetBranch: os.Getenv("GITHUB_BASE_REF"),
+			Url:          repositoryUrl + "/pull/" + id,
+		}
+	}
+	return result
+}
+
+func jenkinsBuildContext() BuildContext {
+	result := BuildContext{
+		Provider:    CIJenkins,
+		Branch:      firstEnv("BRANCH_NAME", "GIT_LOCAL_BRANCH"),
+		Tag:         os.Getenv("TAG_NAME"),
+		Commit:      os.Getenv("GIT_COMMIT"),
+		BuildNumber: os.Getenv("BUILD_NUMBER"),
+		JobUrl:      os.Getenv("BUILD_URL"),
+	}
+	if result.Branch == "" {
+		result.Branch = trimJenkinsRemote(os.Getenv("GIT_BRANCH"))
+	}
+	if id := os.Getenv("CHANGE_ID"); id != "" {
+		result.MergeRequest = &MergeRequest{
+			Id:           id,
+			SourceBranch: os.Getenv("CHANGE_BRANCH"),
+			TargetBranch: os.Getenv("CHANGE_TARGET"),
+			Url:          os.Getenv("CHANGE_URL"),
+		}
+		result.Branch = result.MergeRequest.SourceBranch
+	}
+	if result.Tag != "" && result.Branch == result.Tag {
+		result.Branch = ""
+	}
+	return result
+}
+
+func azureBuildContext() BuildContext {
+	result := BuildContext{
+		Provider:    CIAzure,
+		Commit:      os.Getenv("BUILD_SOURCEVERSION"),
+		BuildNumber: os.Getenv("BUILD_BUILDNUMBER"),
+	}
+	if collectionUrl := os.Getenv("SYSTEM_TEAMFOUNDATIONCOLLECTIONURI"); collectionUrl != "" {
+		result.JobUrl = strings.TrimSuffix(collectionUrl, "/") + "/" + os.Getenv("SYSTEM_TEAMPROJECT") +
+			"/_build/results?buildId=" + os.Getenv("BUILD_BUILDID")
+	}
+	ref := os.Getenv("BUILD_SOURCEBRANCH")
+	switch {
+	case strings.HasPrefix(ref, "refs/heads/"):
+		result.Branch = strings.TrimPrefix(ref, "refs/heads/")
+	case strings.HasPrefix(ref, "refs/tags/"):
+		result.Tag = strings.TrimPrefix(ref, "refs/tags/")
+	}
+	if id := firstEnv("SYSTEM_PULLREQUEST_PULLREQUESTNUMBER", "SYSTEM_PULLREQUEST_PULLREQUESTID"); id != "" {
+		result.MergeRequest = &MergeRequest{
+			Id:           id,
+			SourceBranch: strings.TrimPrefix(os.Getenv("SYSTEM_PULLREQUEST_SOURCEBRANCH"), "refs/heads/"),
+			TargetBranch: strings.TrimPrefix(os.Getenv("SYSTEM_PULLREQUEST_TARGETBRANCH"), "refs/heads/"),
+		}
+		result.Branch = result.MergeRequest.SourceBranch
+	}
+	return result
+}
+
+func bitbucketBuildContext() BuildContext {
+	origin := os.Getenv("BITBUCKET_GIT_HTTP_ORIGIN")
+	result := BuildContext{
+		Provider:    CIBitbucket,
+		Branch:      os.Getenv("BITBUCKET_BRANCH"),
+		Tag:         os.Getenv("BITBUCKET_TAG"),
+		Commit:      os.Getenv("BITBUCKET_COMMIT"),
+		BuildNumber: os.Getenv("BITBUCKET_BUILD_NUMBER"),
+	}
+	if origin != "" {
+		result.JobUrl = origin + "/addon/pipelines/home#!/results/" + result.BuildNumber
+	}
+	if id := os.Getenv("BITBUCKET_PR_ID"); id != "" {
+		result.MergeRequest = &MergeRequest{
+			Id:           id,
+			SourceBranch: result.Branch,
+			TargetBranch: os.Getenv("BITBUCKET_PR_DESTINATION_BRANCH"),
+		}
+		if origin != "" {
+			result.MergeRequest.Url = origin + "/pull-requests/" + id
+		}
+	}
+	return result
+}
+
+// jenkinsRemotePrefixes are removed from GIT_BRANCH, which contains the
+// remote (e.g. origin/feature/foo) depending on the configuration of the job.
+var jenkinsRemotePrefixes = []string{"refs/remotes/origin/", "remotes/origin/", "origin/", "refs/heads/"}
+
+func trimJenkinsRemote(branch string) string {
+	for _, prefix := range jenkinsRemotePrefixes {
+		if strings.HasPrefix(branch, prefix) {
+			return strings.TrimPrefix(branch, prefix)
+		}
+	}
+	return branch
+}
+
+// completeFromGit fills the commit and (for non tag builds) the branch of
+// the given context using git, if the CI system did not provide them. CI
+// systems often check out a detached HEAD or no repository at all, so
+// errors just leave the fields empty.
+func completeFromGit(context *BuildContext) {
+	if context.Commit == "" {
+		if v, err := git.Commit(); err == nil {
+			context.Commit = v
+		}
+	}
+	if context.Branch == "" && context.Tag == "" {
+		if v, err := git.Branch(); err == nil {
+			context.Branch = v
+		}
+	}
+}
+
+func localBuildContext() (BuildContext, error) {
+	result := BuildContext{Provider: CILocal}
+	var err error
+	if result.Commit, err = git.Commit(); err != nil {
+		return BuildContext{}, err
+	}
+	if result.Branch, err = git.Branch(); err != nil {
+		return BuildContext{}, err
+	}
+	if result.Tag, err = git.ExactTag(); err != nil {
+		return BuildContext{}, err
+	}
+	return result, nil
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
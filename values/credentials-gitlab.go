@@ -5,11 +5,10 @@ import (
 	"errors"
 	mhttp "github.com/echocat/mageplus/http"
 	"net/http"
-	"os"
 )
 
 var (
-	NoGitLabCredentialsError = errors.New("there is neither a CI_JOB_TOKEN nor GITLAB_TOKEN set." +
+	NoGitLabCredentialsError = errors.New("there is neither a CI_JOB_TOKEN nor GITLAB_TOKEN (or GITLAB_TOKEN_FILE) set." +
 		" Please go to your GitLab profile page and create yourself a token with at least the permission" +
 		" `read_api` and store it as environment variables GITLAB_TOKEN=<created_token>" +
 		" ; For more details refer: https://docs.gitlab.com/ee/user/profile/personal_access_tokens.html")
@@ -28,10 +27,16 @@ type GitLabCredentials struct {
 }
 
 func GetGitLabCredentials() (GitLabCredentials, error) {
-	if v, ok := os.LookupEnv("CI_JOB_TOKEN"); ok {
+	if v, ok, err := lookup("CI_JOB_TOKEN"); err != nil {
+		return GitLabCredentials{}, err
+	} else if ok {
+		RegisterSecret(v)
 		return GitLabCredentials{GitLabJobToken, v}, nil
 	}
-	if v, ok := os.LookupEnv("GITLAB_TOKEN"); ok {
+	if v, ok, err := lookup("GITLAB_TOKEN"); err != nil {
+		return GitLabCredentials{}, err
+	} else if ok {
+		RegisterSecret(v)
 		return GitLabCredentials{GitLabPrivateToken, v}, nil
 	}
 	return GitLabCredentials{}, NoGitLabCredentialsError
@@ -0,0 +1,107 @@
+package values
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"strconv"
+	"strings"
+)
+
+const (
+	encryptedFormatPrefix = "mageplus:v1"
+	encryptionIterations  = 200000
+	encryptionKeyLength   = 32 // AES-256
+	encryptionSaltLength  = 16
+
+	// maxEncryptionIterations prevents that a tampered file lets every
+	// decryption take ages.
+	maxEncryptionIterations = 2000000
+)
+
+var ErrWrongKey = errors.New("wrong key or corrupted content")
+
+// Encrypt encrypts plain with AES-256-GCM. The AES key is derived from the
+// given key using PBKDF2-HMAC-SHA256 with a random salt. The result is text
+// in the format mageplus:v1:<iterations>:<salt>:<nonce>:<ciphertext>
+// (all binary parts base64 encoded).
+func Encrypt(plain []byte, key string) ([]byte, error) {
+	salt := make([]byte, encryptionSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("cannot generate salt: %v", err)
+	}
+	aead, err := newAead(key, salt, encryptionIterations)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("cannot generate nonce: %v", err)
+	}
+	ciphertext := aead.Seal(nil, nonce, plain, []byte(encryptedFormatPrefix))
+	return []byte(fmt.Sprintf("%s:%d:%s:%s:%s\n",
+		encryptedFormatPrefix,
+		encryptionIterations,
+		base64.StdEncoding.EncodeToString(salt),
+		base64.StdEncoding.EncodeToString(nonce),
+		base64.StdEncoding.EncodeToString(ciphertext),
+	)), nil
+}
+
+// Decrypt decrypts content created by Encrypt. If the key does not match
+// ErrWrongKey is returned.
+func Decrypt(encrypted []byte, key string) ([]byte, error) {
+	plain := strings.TrimSpace(string(encrypted))
+	if !strings.HasPrefix(plain, encryptedFormatPrefix+":") {
+		return nil, errors.New("unsupported format of encrypted content")
+	}
+	parts := strings.Split(strings.TrimPrefix(plain, encryptedFormatPrefix+":"), ":")
+	if len(parts) != 4 {
+		return nil, errors.New("illegal format of encrypted content")
+	}
+	iterations, err := strconv.Atoi(parts[0])
+	if err != nil || iterations <= 0 {
+		return nil, fmt.Errorf("illegal iterations of encrypted content: %s", parts[0])
+	} else if iterations > maxEncryptionIterations {
+		return nil, fmt.Errorf("iterations of encrypted content exceed the maximum of %d: %d", maxEncryptionIterations, iterations)
+	}
+	var salt, nonce, ciphertext []byte
+	for i, target := range []*[]byte{&salt, &nonce, &ciphertext} {
+		if *target, err = base64.StdEncoding.DecodeString(parts[i+1]); err != nil {
+			return nil, fmt.Errorf("illegal format of encrypted content: %v", err)
+		}
+	}
+	aead, err := newAead(key, salt, iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("illegal nonce of encrypted content")
+	}
+	result, err := aead.Open(nil, nonce, ciphertext, []byte(encryptedFormatPrefix))
+	if err != nil {
+		return nil, ErrWrongKey
+	}
+	return result, nil
+}
+
+func newAead(key string, salt []byte, iterations int) (cipher.AEAD, error) {
+	if key == "" {
+		return nil, errors.New("empty key")
+	}
+	block, err := aes.NewCipher(deriveKey(key, salt, iterations))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// deriveKey derives the AES key from the given key using PBKDF2-HMAC-SHA256.
+func deriveKey(key string, salt []byte, iterations int) []byte {
+	return pbkdf2.Key([]byte(key), salt, iterations, encryptionKeyLength, sha256.New)
+}
@@ -0,0 +1,72 @@
+package values
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDeriveKey(t *testing.T) {
+	// Test vectors of PBKDF2-HMAC-SHA256 of RFC 7914 (section 11, first 32
+	// bytes) and the ones of RFC 6070 applied to HMAC-SHA256.
+	cases := []struct {
+		key        string
+		salt       string
+		iterations int
+		expected   string
+	}{
+		{"passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"},
+		{"Password", "NaCl", 80000, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56"},
+		{"password", "salt", 1, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+		{"password", "salt", 4096, "c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+		{"passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, "348c89dbcbd32b2f32d814b8116e84cf2b17347ebc1800181c4e2a1fb8dd53e1"},
+	}
+	for _, c := range cases {
+		expected, err := hex.DecodeString(c.expected)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := deriveKey(c.key, []byte(c.salt), c.iterations)
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("deriveKey(%q, %q, %d): expected %x but got %x", c.key, c.salt, c.iterations, expected, actual)
+		}
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	plain := []byte("FOO=bar\nSECRET=s3cr3t\n")
+	encrypted, err := Encrypt(plain, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(encrypted, []byte("s3cr3t")) {
+		t.Errorf("encrypted content contains the plain secret: %s", encrypted)
+	}
+
+	decrypted, err := Decrypt(encrypted, "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(decrypted, plain) {
+		t.Errorf("expected %q but got %q", plain, decrypted)
+	}
+
+	if _, err := Decrypt(encrypted, "other"); err != ErrWrongKey {
+		t.Errorf("expected %v but got %v", ErrWrongKey, err)
+	}
+}
+
+func TestDecryptRejectsTooManyIterations(t *testing.T) {
+	encrypted, err := Encrypt([]byte("FOO=bar"), "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := strings.Replace(string(encrypted), ":200000:", ":2000000000:", 1)
+	if tampered == string(encrypted) {
+		t.Fatalf("unexpected format: %s", encrypted)
+	}
+	if _, err := Decrypt([]byte(tampered), "key"); err == nil || !strings.Contains(err.Error(), "exceed the maximum") {
+		t.Errorf("expected error about the maximum of iterations but got %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package values
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// EnvDefines contains the comma separated names of the variables which were
+// set using -D name=value on the command line of mageplus.
+const EnvDefines = "MAGEPLUS_DEFINES"
+
+// Kind is the type of the value of a declared variable.
+type Kind string
+
+const (
+	KindString   Kind = "string"
+	KindBool     Kind = "bool"
+	KindInt      Kind = "int"
+	KindDuration Kind = "duration"
+	KindUrl      Kind = "url"
+	KindList     Kind = "list"
+	KindEnum     Kind = "enum"
+)
+
+// Declaration describes a variable which is used by a magefile.
+//
+//	func init() {
+//		values.MustDeclare(
+//			values.Declaration{Name: "REGISTRY", Description: "Docker registry to push to", Kind: values.KindUrl},
+//			values.Declaration{Name: "REGISTRY_TOKEN", Description: "Token of REGISTRY", Secret: true},
+//		)
+//	}
+//
+// Values set using -D name=value are only type checked if they are declared;
+// run Validate (for example using mg.Deps(values.Validate)) to be warned about
+// the ones which are not.
+type Declaration struct {
+	Name        string
+	Description string
+	Kind        Kind     // Empty means KindString
+	Allowed     []string // Allowed values of KindEnum
+	Separator   string   // Separator of KindList; empty means ","
+	Secret      bool     // If true the value is treated as secret
+}
+
+var (
+	declarations      = map[string]Declaration{}
+	declarationsMutex sync.RWMutex
+)
+
+// Declare registers the given declarations and validates the current values
+// of the variables (see Declaration.Validate). All problems are reported
+// together.
+func Declare(candidates ...Declaration) error {
+	var errs Errors
+	declarationsMutex.Lock()
+	for _, candidate := range candidates {
+		if candidate.Name == "" {
+			errs = append(errs, fmt.Errorf("declaration without name: %+v", candidate))
+			continue
+		}
+		declarations[candidate.Name] = candidate
+	}
+	declarationsMutex.Unlock()
+
+	for _, candidate := range candidates {
+		if candidate.Name == "" {
+			continue
+		}
+		if err := candidate.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// MustDeclare is like Declare but exits the process on errors.
+func MustDeclare(candidates ...Declaration) {
+	if err := Declare(candidates...); err != nil {
+		errorLog.Fatalln("Error:", err)
+	}
+}
+
+// Declared returns the Declaration of the variable with the given name.
+func Declared(name string) (Declaration, bool) {
+	declarationsMutex.RLock()
+	defer declarationsMutex.RUnlock()
+	result, ok := declarations[name]
+	return result, ok
+}
+
+// Validate validates the current values of all declared variables and warns
+// about variables set using -D name=value which are not declared, because
+// those are used without any check of their values.
+func Validate() error {
+	declarationsMutex.RLock()
+	candidates := make([]Declaration, 0, len(declarations))
+	for _, candidate := range declarations {
+		candidates = append(candidates, candidate)
+	}
+	declarationsMutex.RUnlock()
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Name < candidates[j].Name
+	})
+
+	var errs Errors
+	for _, candidate := range candidates {
+		if err := candidate.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, name := range UndeclaredDefines() {
+		errorLog.Printf("Warning: -D %s does not match any declared variable; its value is not checked.", name)
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// UndeclaredDefines returns the names of the variables which were set using
+// -D name=value on the command line of mageplus but are not declared.
+func UndeclaredDefines() []string {
+	var result []string
+	for _, name := range strings.Split(os.Getenv(EnvDefines), ",") {
+		if _, ok := Declared(name); name != "" && !ok {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+// IsDefined returns true if the variable with the given name was set using
+// -D name=value on the command line of mageplus.
+func IsDefined(name string) bool {
+	for _, candidate := range strings.Split(os.Getenv(EnvDefines), ",") {
+		if candidate == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks if the current value of the variable, if present, matches
+// the Kind of the declaration. Values of secret declarations are registered
+// as secrets.
+func (instance Declaration) Validate() error {
+	var err error
+	switch instance.Kind {
+	case KindBool:
+		_, err = Bool(instance.Name, false)
+	case KindInt:
+		_, err = Int(instance.Name, 0)
+	case KindDuration:
+		_, err = Duration(instance.Name, 0)
+	case KindUrl:
+		_, err = Url(instance.Name, nil)
+	case KindList:
+		_, err = List(instance.Name, instance.separator(), nil)
+	case KindEnum:
+		_, err = Enum(instance.Name, "", instance.Allowed...)
+	case KindString, "":
+		_, _, err = lookup(instance.Name)
+	default:
+		return fmt.Errorf("variable '%s' is declared with unknown kind '%s'", instance.Name, instance.Kind)
+	}
+	if err != nil && IsDefined(instance.Name) {
+		return fmt.Errorf("-D %s: %v", instance.Name, err)
+	} else if err != nil {
+		return err
+	}
+	if instance.Secret {
+		if v, ok, _ := lookup(instance.Name); ok {
+			RegisterSecret(v)
+		}
+	}
+	return nil
+}
+
+func (instance Declaration) separator() string {
+	if instance.Separator == "" {
+		return ","
+	}
+	return instance.Separator
+}
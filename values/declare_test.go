@@ -0,0 +1,51 @@
+package values
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateWarnsAboutUndeclaredDefines(t *testing.T) {
+	defer setTestVariable(t, EnvDefines, value("MAGEPLUS_TEST_DECLARED,MAGEPLUS_TEST_UNDECLARED"))()
+	defer setTestVariable(t, "MAGEPLUS_TEST_DECLARED", value("3"))()
+	defer setTestVariable(t, "MAGEPLUS_TEST_UNDECLARED", value("whatever"))()
+	if err := Declare(Declaration{Name: "MAGEPLUS_TEST_DECLARED", Kind: KindInt}); err != nil {
+		t.Fatal(err)
+	}
+
+	if actual := UndeclaredDefines(); !reflect.DeepEqual(actual, []string{"MAGEPLUS_TEST_UNDECLARED"}) {
+		t.Errorf("expected [MAGEPLUS_TEST_UNDECLARED] but got %v", actual)
+	}
+
+	buf := new(bytes.Buffer)
+	errorLog.SetOutput(buf)
+	defer errorLog.SetOutput(os.Stderr)
+	if err := Validate(); err != nil {
+		t.Errorf("expected no error but got %v", err)
+	}
+	if actual := buf.String(); !strings.Contains(actual, "-D MAGEPLUS_TEST_UNDECLARED does not match") || strings.Contains(actual, "MAGEPLUS_TEST_DECLARED") {
+		t.Errorf("unexpected warnings: %q", actual)
+	}
+}
+
+func TestValidateChecksDefinedValues(t *testing.T) {
+	defer setTestVariable(t, EnvDefines, value("MAGEPLUS_TEST_DEFINED_INT"))()
+	defer setTestVariable(t, "MAGEPLUS_TEST_DEFINED_INT", value("3"))()
+	if err := Declare(Declaration{Name: "MAGEPLUS_TEST_DEFINED_INT", Kind: KindInt}); err != nil {
+		t.Fatal(err)
+	}
+
+	defer setTestVariable(t, "MAGEPLUS_TEST_DEFINED_INT", value("many"))()
+	err := Validate()
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 1 {
+		t.Fatalf("expected 1 error but got %v", err)
+	}
+	if !strings.HasPrefix(errs[0].Error(), "-D MAGEPLUS_TEST_DEFINED_INT: ") {
+		t.Errorf("expected error to mention -D but got %v", errs[0])
+	}
+}
@@ -0,0 +1,169 @@
+package values
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"github.com/joho/godotenv"
+	"io/ioutil"
+	"os"
+	"strings"
+)
+
+const (
+	// PlainDotEnvFile is the dotenv file which contains the local settings
+	// of the user and should not be committed.
+	PlainDotEnvFile = ".env.mage"
+
+	// EncryptedDotEnvFile is the dotenv file which is encrypted using Encrypt
+	// and could be therefore committed together with the project.
+	EncryptedDotEnvFile = ".env.mage.enc"
+
+	// EnvDotEnvKey contains the key of EncryptedDotEnvFile. It could be also
+	// read from a file using MAGEPLUS_ENV_KEY_FILE.
+	EnvDotEnvKey = "MAGEPLUS_ENV_KEY"
+
+	encryptedDotEnvSuffix = ".enc"
+)
+
+var (
+	// DotEnvFilesCandidates are the files which are loaded into the
+	// environment by mageplus if present. Values of earlier files take
+	// precedence. Files ending with .enc are decrypted first.
+	DotEnvFilesCandidates = []string{PlainDotEnvFile, EncryptedDotEnvFile, ".env.build", ".env", ".env.example"}
+
+	ErrNoDotEnvKey = errors.New("there is no key for " + EncryptedDotEnvFile + " present;" +
+		" set it using " + EnvDotEnvKey + " or " + EnvDotEnvKey + FileSuffix)
+)
+
+// ResolveDotEnvFiles returns all files of DotEnvFilesCandidates which exist.
+func ResolveDotEnvFiles() ([]string, error) {
+	var result []string
+	for _, candidate := range DotEnvFilesCandidates {
+		if exist, err := mio.FileExists(candidate); err != nil {
+			return nil, err
+		} else if exist {
+			result = append(result, candidate)
+		}
+	}
+	return result, nil
+}
+
+// LoadDotEnv loads all existing DotEnvFilesCandidates into the environment.
+// Variables which are already present are not overwritten. If prompt is true
+// and the key of encrypted files is not present it is requested from the
+// terminal. Encrypted files are skipped if there is no key (with a warning
+// if prompt is true).
+func LoadDotEnv(prompt bool) error {
+	return forEachDotEnv(prompt, func(file string, content map[string]string) error {
+		for key, value := range content {
+			if _, ok := os.LookupEnv(key); ok {
+				continue
+			}
+			if err := os.Setenv(key, value); err != nil {
+				return fmt.Errorf("cannot set variable '%s' of '%s': %v", key, file, err)
+			}
+		}
+		return nil
+	})
+}
+
+// ReadDotEnv returns the content of all existing DotEnvFilesCandidates with
+// the same precedence which is used by LoadDotEnv. Encrypted files are only
+// respected if their key is present.
+func ReadDotEnv() (map[string]string, error) {
+	result := map[string]string{}
+	if err := forEachDotEnv(false, func(_ string, content map[string]string) error {
+		for key, value := range content {
+			if _, ok := result[key]; !ok {
+				result[key] = value
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ReadDotEnvFile reads the given dotenv file, which is decrypted using key
+// if its name ends with .enc. All values of encrypted files are registered
+// as secrets.
+func ReadDotEnvFile(file string, key string) (map[string]string, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read '%s': %v", file, err)
+	}
+	encrypted := strings.HasSuffix(file, encryptedDotEnvSuffix)
+	if encrypted {
+		if content, err = Decrypt(content, key); err != nil {
+			return nil, fmt.Errorf("cannot decrypt '%s': %v", file, err)
+		}
+	}
+	result, err := godotenv.Parse(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse '%s': %v", file, err)
+	}
+	if encrypted {
+		for _, value := range result {
+			RegisterSecret(value)
+		}
+	}
+	return result, nil
+}
+
+// DotEnvKey returns the key of EncryptedDotEnvFile from EnvDotEnvKey. If it
+// is not present and prompt is true it is requested from the terminal,
+// otherwise ErrNoDotEnvKey is returned.
+func DotEnvKey(prompt bool) (string, error) {
+	if v, ok, err := lookup(EnvDotEnvKey); err != nil {
+		return "", err
+	} else if ok && v != "" {
+		RegisterSecret(v)
+		return v, nil
+	}
+	if !prompt || !mio.IsTerminal(os.Stdin) {
+		return "", ErrNoDotEnvKey
+	}
+	v, err := mio.PromptHidden(fmt.Sprintf("Key of %s: ", EncryptedDotEnvFile))
+	if err != nil {
+		return "", err
+	}
+	if v == "" {
+		return "", ErrNoDotEnvKey
+	}
+	RegisterSecret(v)
+	return v, nil
+}
+
+func forEachDotEnv(prompt bool, f func(file string, content map[string]string) error) error {
+	files, err := ResolveDotEnvFiles()
+	if err != nil {
+		return err
+	}
+	var key string
+	for _, file := range files {
+		if strings.HasSuffix(file, encryptedDotEnvSuffix) && key == "" {
+			// Without a key (like in CI or for contributors who do not know
+			// it) the shared secrets are just not available. Only a wrong
+			// key fails while decrypting.
+			if key, err = DotEnvKey(prompt); err == ErrNoDotEnvKey {
+				if prompt {
+					errorLog.Printf("Warning: %s is skipped because %v", file, err)
+				}
+				continue
+			} else if err != nil {
+				return err
+			}
+		}
+		content, err := ReadDotEnvFile(file, key)
+		if err != nil {
+			return err
+		}
+		if err := f(file, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
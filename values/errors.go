@@ -0,0 +1,49 @@
+package values
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MissingValueError is returned if a required variable is not present.
+type MissingValueError struct {
+	Name string
+}
+
+func (instance *MissingValueError) Error() string {
+	return fmt.Sprintf("required variable '%s' not present", instance.Name)
+}
+
+// InvalidValueError is returned if the value of a variable cannot be
+// converted into the requested type.
+type InvalidValueError struct {
+	Name     string
+	Value    string
+	Expected string // Description of what was expected, e.g. "bool"
+	Err      error
+}
+
+func (instance *InvalidValueError) Error() string {
+	if instance.Err == nil {
+		return fmt.Sprintf("variable '%s' has illegal value %q; expected %s", instance.Name, instance.Value, instance.Expected)
+	}
+	return fmt.Sprintf("variable '%s' has illegal value %q; expected %s: %v", instance.Name, instance.Value, instance.Expected, instance.Err)
+}
+
+func (instance *InvalidValueError) Unwrap() error {
+	return instance.Err
+}
+
+// Errors contains several problems with variables which are reported together.
+type Errors []error
+
+func (instance Errors) Error() string {
+	if len(instance) == 1 {
+		return instance[0].Error()
+	}
+	lines := make([]string, len(instance))
+	for i, err := range instance {
+		lines[i] = "\n\t" + err.Error()
+	}
+	return fmt.Sprintf("%d problems with variables:%s", len(instance), strings.Join(lines, ""))
+}
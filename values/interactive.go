@@ -0,0 +1,103 @@
+package values
+
+import (
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"github.com/joho/godotenv"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// EnvInteractive could be set to false to prevent that missing required
+// variables are requested from the terminal.
+const EnvInteractive = "MAGEPLUS_INTERACTIVE"
+
+// IsInteractive returns true if missing required variables could be
+// requested from the user. This is the case if stdin is a terminal, the
+// process is not running inside of a CI system and EnvInteractive is not
+// set to false.
+func IsInteractive() bool {
+	if v, ok := os.LookupEnv(EnvInteractive); ok {
+		if enabled, err := strconv.ParseBool(strings.TrimSpace(v)); err == nil && !enabled {
+			return false
+		}
+	}
+	if os.Getenv("CI") != "" || ciProvider() != CILocal {
+		return false
+	}
+	return mio.IsTerminal(os.Stdin)
+}
+
+// promptForMissing requests the value of the missing variable with the given
+// name from the terminal, if IsInteractive. The answer is set in the
+// environment and could be saved to PlainDotEnvFile on request. An empty
+// answer is treated as still missing.
+func promptForMissing(name string) (string, bool, error) {
+	if !IsInteractive() {
+		return "", false, nil
+	}
+	secret, err := IsSecretName(name)
+	if err != nil {
+		return "", false, err
+	}
+
+	message := name
+	if declaration, ok := Declared(name); ok && declaration.Description != "" {
+		message = fmt.Sprintf("%s (%s)", declaration.Description, name)
+	}
+	var v string
+	if secret {
+		v, err = mio.PromptHidden(fmt.Sprintf("%s: ", message))
+	} else {
+		v, err = mio.Prompt(fmt.Sprintf("%s: ", message))
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("cannot read variable '%s': %v", name, err)
+	}
+	if v == "" {
+		return "", false, nil
+	}
+	if err := os.Setenv(name, v); err != nil {
+		return "", false, fmt.Errorf("cannot set variable '%s': %v", name, err)
+	}
+	registerIfSecret(name, v)
+
+	answer, err := mio.Prompt(fmt.Sprintf("Save %s to %s? [y/N]: ", name, PlainDotEnvFile))
+	if err != nil {
+		return "", false, fmt.Errorf("cannot read answer: %v", err)
+	}
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer == "y" || answer == "yes" {
+		if err := appendToDotEnv(PlainDotEnvFile, name, v); err != nil {
+			return "", false, err
+		}
+	}
+	return v, true, nil
+}
+
+func appendToDotEnv(file, name, value string) error {
+	line, err := godotenv.Marshal(map[string]string{name: value})
+	if err != nil {
+		return fmt.Errorf("cannot save variable '%s' to '%s': %v", name, file, err)
+	}
+	existing, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot read '%s': %v", file, err)
+	}
+	if len(existing) > 0 && existing[len(existing)-1] != '\n' {
+		line = "\n" + line
+	}
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("cannot open '%s': %v", file, err)
+	}
+	_, err = f.WriteString(line + "\n")
+	if cErr := f.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write '%s': %v", file, err)
+	}
+	return nil
+}
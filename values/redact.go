@@ -0,0 +1,197 @@
+package values
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// EnvRedacting is set to true by mageplus for the magefile process if the
+// output of the magefile passes a RedactingWriter. Secrets registered inside
+// of the magefile process are then announced to mageplus using an escape
+// sequence inside of stdout and stderr, which the RedactingWriter removes.
+const EnvRedacting = "MAGEPLUS_REDACTING"
+
+var (
+	secretAnnouncementPrefix     = []byte("\x1b]mageplus-secret;")
+	secretAnnouncementTerminator = byte('\a')
+
+	// redactingChildren is true inside of the process which redacts the
+	// output of its children, so it does not announce secrets itself.
+	redactingChildren bool
+)
+
+// RedactChildren sets EnvRedacting for all child processes started
+// afterwards. Their output has to pass a RedactingWriter.
+func RedactChildren() error {
+	redactingChildren = true
+	return os.Setenv(EnvRedacting, "true")
+}
+
+func announceSecret(value string) {
+	if redactingChildren || os.Getenv(EnvRedacting) != "true" {
+		return
+	}
+	announcement := append(append([]byte{}, secretAnnouncementPrefix...), base64.StdEncoding.EncodeToString([]byte(value))...)
+	announcement = append(announcement, secretAnnouncementTerminator)
+	// Both streams are redacted independently, so both have to know the
+	// secret before it could be written to them.
+	_, _ = os.Stdout.Write(announcement)
+	_, _ = os.Stderr.Write(announcement)
+}
+
+// RedactingWriter replaces all registered secrets (see RegisterSecret) by ***
+// before the content is written to the target. Content which could be the
+// beginning of a secret is held back until it is clear that it is not, so
+// Flush has to be called after the last write. Secrets announced by the
+// magefile (see EnvRedacting) are registered and removed from the content.
+type RedactingWriter struct {
+	target  io.Writer
+	pending []byte
+	mutex   sync.Mutex
+}
+
+func NewRedactingWriter(target io.Writer) *RedactingWriter {
+	return &RedactingWriter{target: target}
+}
+
+func (instance *RedactingWriter) Write(p []byte) (int, error) {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+
+	instance.pending = append(instance.pending, p...)
+	in, incomplete := extractSecretAnnouncements(instance.pending)
+	out, rest := currentSecretMatcher().redact(in, false)
+	instance.pending = append(append([]byte{}, rest...), incomplete...)
+	if _, err := instance.target.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes all content which was held back.
+func (instance *RedactingWriter) Flush() error {
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+
+	if len(instance.pending) == 0 {
+		return nil
+	}
+	in, incomplete := extractSecretAnnouncements(instance.pending)
+	out, _ := currentSecretMatcher().redact(append(in, incomplete...), true)
+	instance.pending = instance.pending[:0]
+	_, err := instance.target.Write(out)
+	return err
+}
+
+// extractSecretAnnouncements registers the secrets of all announcements
+// inside of in and returns the remaining content. An incomplete announcement
+// at the end of in is returned separately.
+func extractSecretAnnouncements(in []byte) (out []byte, incomplete []byte) {
+	buf := new(bytes.Buffer)
+	for {
+		i := bytes.Index(in, secretAnnouncementPrefix)
+		if i < 0 {
+			n := len(in) - partialPrefixLength(in, secretAnnouncementPrefix)
+			buf.Write(in[:n])
+			return buf.Bytes(), in[n:]
+		}
+		buf.Write(in[:i])
+		end := bytes.IndexByte(in[i:], secretAnnouncementTerminator)
+		if end < 0 {
+			return buf.Bytes(), in[i:]
+		}
+		encoded := in[i+len(secretAnnouncementPrefix) : i+end]
+		if secret, err := base64.StdEncoding.DecodeString(string(encoded)); err == nil {
+			addSecret(string(secret))
+		}
+		in = in[i+end+1:]
+	}
+}
+
+// partialPrefixLength returns the length of the longest end of in which is
+// the beginning of prefix.
+func partialPrefixLength(in, prefix []byte) int {
+	for n := len(prefix) - 1; n > 0; n-- {
+		if len(in) >= n && bytes.Equal(in[len(in)-n:], prefix[:n]) {
+			return n
+		}
+	}
+	return 0
+}
+
+// secretMatcher finds secrets inside of content. The secrets are indexed by
+// their first byte, so most positions of the content are checked by one
+// lookup.
+type secretMatcher struct {
+	byFirstByte [256][][]byte // Longest first
+	maxLength   int
+}
+
+func newSecretMatcher(secrets map[string]bool) *secretMatcher {
+	result := &secretMatcher{}
+	for secret := range secrets {
+		b := []byte(secret)
+		result.byFirstByte[b[0]] = append(result.byFirstByte[b[0]], b)
+		if len(b) > result.maxLength {
+			result.maxLength = len(b)
+		}
+	}
+	for _, candidates := range result.byFirstByte {
+		sort.Slice(candidates, func(i, j int) bool {
+			if len(candidates[i]) != len(candidates[j]) {
+				return len(candidates[i]) > len(candidates[j])
+			}
+			return bytes.Compare(candidates[i], candidates[j]) < 0
+		})
+	}
+	return result
+}
+
+// redact replaces every secret inside of in. If final is false the end of in
+// which could be the beginning of a secret is returned as rest instead of
+// being part of out.
+func (instance *secretMatcher) redact(in []byte, final bool) (out []byte, rest []byte) {
+	if instance.maxLength == 0 {
+		return in, nil
+	}
+	buf := new(bytes.Buffer)
+	start := 0
+	for i := 0; i < len(in); {
+		if n := instance.secretAt(in[i:]); n > 0 {
+			buf.Write(in[start:i])
+			buf.WriteString(redacted)
+			i += n
+			start = i
+		} else if !final && len(in)-i < instance.maxLength && instance.isSecretPrefix(in[i:]) {
+			buf.Write(in[start:i])
+			return buf.Bytes(), in[i:]
+		} else {
+			i++
+		}
+	}
+	buf.Write(in[start:])
+	return buf.Bytes(), nil
+}
+
+// secretAt returns the length of the secret in at the beginning of in or 0.
+func (instance *secretMatcher) secretAt(in []byte) int {
+	for _, secret := range instance.byFirstByte[in[0]] {
+		if bytes.HasPrefix(in, secret) {
+			return len(secret)
+		}
+	}
+	return 0
+}
+
+func (instance *secretMatcher) isSecretPrefix(in []byte) bool {
+	for _, secret := range instance.byFirstByte[in[0]] {
+		if len(in) < len(secret) && bytes.HasPrefix(secret, in) {
+			return true
+		}
+	}
+	return false
+}
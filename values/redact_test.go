@@ -0,0 +1,41 @@
+package values
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+)
+
+func TestRedactingWriter(t *testing.T) {
+	addSecret("redact-test-secret")
+	addSecret("redact-test-secret-longer")
+	addSecret("other-redact-test")
+
+	cases := []struct {
+		writes   []string
+		expected string
+	}{
+		{[]string{"nothing secret"}, "nothing secret"},
+		{[]string{"a redact-test-secret b"}, "a *** b"},
+		{[]string{"redact-test-secret-longer!"}, "***!"},
+		{[]string{"x redact-te", "st-secret y"}, "x *** y"},
+		{[]string{"other-redact-test", "redact-test-secret"}, "******"},
+		{[]string{"ends with redact-test"}, "ends with redact-test"},
+		{[]string{"\x1b]mageplus-secret;" + base64.StdEncoding.EncodeToString([]byte("announced-redact-test")), "\aannounced-redact-test"}, "***"},
+	}
+	for _, c := range cases {
+		buf := new(bytes.Buffer)
+		w := NewRedactingWriter(buf)
+		for _, write := range c.writes {
+			if _, err := w.Write([]byte(write)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := w.Flush(); err != nil {
+			t.Fatal(err)
+		}
+		if actual := buf.String(); actual != c.expected {
+			t.Errorf("%q: expected %q but got %q", c.writes, c.expected, actual)
+		}
+	}
+}
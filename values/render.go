@@ -0,0 +1,221 @@
+package values
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/blang/semver"
+	"github.com/echocat/mageplus/git"
+	"github.com/echocat/mageplus/sdk"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+var (
+	renderDotEnvOnce sync.Once
+	renderDotEnv     map[string]string
+	renderDotEnvErr  error
+)
+
+// RenderData is the data templates of Render are executed against. Git and
+// Sdk are only evaluated if the template uses them.
+//
+//	{{ .Env.REGISTRY }}/app:{{ .Git.Tag | default "latest" }}
+//	app-{{ major .Git.Tag }}.{{ minor .Git.Tag }}-{{ .Os }}-{{ .Arch }}.tar.gz
+type RenderData struct {
+	Env    map[string]string // Environment variables, including the ones loaded from dotenv files by mageplus
+	DotEnv map[string]string // Values of the dotenv files (see DotEnvFilesCandidates)
+	Git    RenderGit
+	Sdk    RenderSdk
+	Os     string // GOOS of the current platform
+	Arch   string // GOARCH of the current platform
+}
+
+// RenderGit provides the metadata of the git repository of the current
+// working directory.
+type RenderGit struct{}
+
+// Tag returns the latest tag reachable from HEAD or an empty string if there
+// is none.
+func (instance RenderGit) Tag() (string, error) {
+	v, err := git.Tag()
+	if err == git.ErrNoTag {
+		return "", nil
+	}
+	return v, err
+}
+
+// Commit returns the full hash of HEAD.
+func (instance RenderGit) Commit() (string, error) {
+	return git.Commit()
+}
+
+// ShortCommit returns the abbreviated hash of HEAD.
+func (instance RenderGit) ShortCommit() (string, error) {
+	return git.ShortCommit()
+}
+
+// Branch returns the name of the current branch or an empty string if HEAD
+// is detached.
+func (instance RenderGit) Branch() (string, error) {
+	return git.Branch()
+}
+
+// RenderSdk provides information about the golang SDK which is used by the
+// current process (see GOROOT and PATH).
+type RenderSdk struct{}
+
+func (instance RenderSdk) Version() (string, error) {
+	s, err := currentSdk()
+	return s.Version.String(), err
+}
+
+func (instance RenderSdk) Root() (string, error) {
+	s, err := currentSdk()
+	return s.Root, err
+}
+
+func (instance RenderSdk) GoBinary() (string, error) {
+	s, err := currentSdk()
+	return s.GoBinary, err
+}
+
+// NewRenderData creates the RenderData of the current process. The dotenv
+// files are only read (and decrypted) once per process.
+func NewRenderData() (RenderData, error) {
+	renderDotEnvOnce.Do(func() {
+		renderDotEnv, renderDotEnvErr = ReadDotEnv()
+	})
+	if renderDotEnvErr != nil {
+		return RenderData{}, renderDotEnvErr
+	}
+	dotEnv := make(map[string]string, len(renderDotEnv))
+	for k, v := range renderDotEnv {
+		dotEnv[k] = v
+	}
+	env := map[string]string{}
+	for _, entry := range os.Environ() {
+		if parts := strings.SplitN(entry, "=", 2); len(parts) == 2 {
+			env[parts[0]] = parts[1]
+		}
+	}
+	return RenderData{
+		Env:    env,
+		DotEnv: dotEnv,
+		Os:     runtime.GOOS,
+		Arch:   runtime.GOARCH,
+	}, nil
+}
+
+// Render executes the given text/template against NewRenderData. Besides
+// the builtin functions of text/template the following functions are
+// available:
+//
+//	env "NAME"             value of a variable (supports NAME_FILE), empty if absent
+//	default "def" value    def if value is empty
+//	required "msg" value   fails with msg if value is empty
+//	lower, upper, trim     like the functions of the strings package
+//	replace "old" "new" s  replaces all old with new in s
+//	semver value           parses value as semantic version (a leading "v" is allowed)
+//	major, minor, patch    the parts of a semantic version
+//	prerelease             the pre-release part of a semantic version (e.g. rc.1)
+func Render(tmpl string) (string, error) {
+	data, err := NewRenderData()
+	if err != nil {
+		return "", err
+	}
+	return RenderWith(tmpl, data)
+}
+
+// RenderWith is like Render but executes the template against the given data.
+func RenderWith(tmpl string, data RenderData) (string, error) {
+	t, err := template.New("").Option("missingkey=zero").Funcs(renderFuncs).Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse template %q: %v", tmpl, err)
+	}
+	buf := new(bytes.Buffer)
+	if err := t.Execute(buf, data); err != nil {
+		return "", fmt.Errorf("cannot render template %q: %v", tmpl, err)
+	}
+	return buf.String(), nil
+}
+
+// MustRender is like Render but exits the process on errors.
+func MustRender(tmpl string) string {
+	v, err := Render(tmpl)
+	if err != nil {
+		errorLog.Fatalln("Error:", err)
+	}
+	return v
+}
+
+var renderFuncs = template.FuncMap{
+	"env": func(name string) (string, error) {
+		v, _, err := lookup(name)
+		return v, err
+	},
+	"default": func(def string, value string) string {
+		if value == "" {
+			return def
+		}
+		return value
+	},
+	"required": func(message string, value string) (string, error) {
+		if value == "" {
+			return "", errors.New(message)
+		}
+		return value, nil
+	},
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+	"trim":    strings.TrimSpace,
+	"replace": func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"semver":  parseSemver,
+	"major": func(value string) (uint64, error) {
+		v, err := parseSemver(value)
+		return v.Major, err
+	},
+	"minor": func(value string) (uint64, error) {
+		v, err := parseSemver(value)
+		return v.Minor, err
+	},
+	"patch": func(value string) (uint64, error) {
+		v, err := parseSemver(value)
+		return v.Patch, err
+	},
+	"prerelease": func(value string) (string, error) {
+		v, err := parseSemver(value)
+		if err != nil {
+			return "", err
+		}
+		parts := make([]string, len(v.Pre))
+		for i, pre := range v.Pre {
+			parts[i] = pre.String()
+		}
+		return strings.Join(parts, "."), nil
+	},
+}
+
+func parseSemver(value string) (semver.Version, error) {
+	v, err := semver.ParseTolerant(value)
+	if err != nil {
+		return semver.Version{}, fmt.Errorf("illegal semantic version %q: %v", value, err)
+	}
+	return v, nil
+}
+
+func currentSdk() (sdk.Sdk, error) {
+	s, err := sdk.EvalFromGoroot()
+	if err == sdk.ErrNoGoSdk {
+		s, err = sdk.EvalFromPath()
+	}
+	if err != nil {
+		return sdk.Sdk{}, err
+	} else if s.GoBinary == "" {
+		return sdk.Sdk{}, sdk.ErrNoGoSdk
+	}
+	return s, nil
+}
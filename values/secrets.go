@@ -0,0 +1,162 @@
+package values
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// EnvSecretPatterns contains additional comma separated regular
+	// expressions for SecretNamePatterns.
+	EnvSecretPatterns = "MAGEPLUS_SECRET_PATTERNS"
+
+	// minSecretLength prevents that short values like "1" or "true" are
+	// redacted all over the output.
+	minSecretLength = 4
+
+	redacted = "***"
+)
+
+var (
+	// SecretNamePatterns matches the names of variables which contain secrets.
+	// CREDENTIALS is not part of it, because variables like
+	// GOOGLE_APPLICATION_CREDENTIALS contain the path of a file.
+	SecretNamePatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)(TOKEN|SECRET|PASSWORD|PASSWD|PASSPHRASE|API_?KEY|PRIVATE_?KEY)`),
+	}
+
+	secrets      = map[string]bool{}
+	secretsMatch *secretMatcher // Built from secrets on demand; nil if outdated
+	secretsMutex sync.RWMutex
+)
+
+// RegisterSecret registers the given value as secret, which will be
+// replaced by *** in every output passing a RedactingWriter. Inside of the
+// magefile process the secret is also announced to mageplus (see
+// EnvRedacting), which redacts the output of the magefile.
+func RegisterSecret(value string) {
+	if addSecret(value) {
+		announceSecret(strings.TrimSpace(value))
+	}
+}
+
+// addSecret registers the given value as secret and returns true if it was
+// not registered before.
+func addSecret(value string) bool {
+	value = strings.TrimSpace(value)
+	if len(value) < minSecretLength {
+		return false
+	}
+	secretsMutex.Lock()
+	defer secretsMutex.Unlock()
+	if secrets[value] {
+		return false
+	}
+	secrets[value] = true
+	secretsMatch = nil
+	return true
+}
+
+// currentSecretMatcher returns the secretMatcher of all registered secrets.
+func currentSecretMatcher() *secretMatcher {
+	secretsMutex.RLock()
+	result := secretsMatch
+	secretsMutex.RUnlock()
+	if result != nil {
+		return result
+	}
+
+	secretsMutex.Lock()
+	defer secretsMutex.Unlock()
+	if secretsMatch == nil {
+		secretsMatch = newSecretMatcher(secrets)
+	}
+	return secretsMatch
+}
+
+// Secrets returns all registered secrets, the longest first.
+func Secrets() []string {
+	secretsMutex.RLock()
+	defer secretsMutex.RUnlock()
+	result := make([]string, 0, len(secrets))
+	for secret := range secrets {
+		result = append(result, secret)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i]) != len(result[j]) {
+			return len(result[i]) > len(result[j])
+		}
+		return result[i] < result[j]
+	})
+	return result
+}
+
+// IsSecretName returns true if the variable with the given name contains a
+// secret according to its Declaration, SecretNamePatterns or
+// EnvSecretPatterns.
+func IsSecretName(name string) (bool, error) {
+	patterns, err := secretNamePatterns()
+	if err != nil {
+		return false, err
+	}
+	name = strings.TrimSuffix(name, FileSuffix)
+	if declaration, ok := Declared(name); ok && declaration.Secret {
+		return true, nil
+	}
+	for _, pattern := range patterns {
+		if pattern.MatchString(name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RegisterSecretsFromEnv registers the values of all environment variables
+// whose name is a secret name (see IsSecretName) as secrets, including the
+// ones which are referenced by <NAME>_FILE. Files which cannot be read are
+// skipped with a warning, because the targets may not need them.
+func RegisterSecretsFromEnv() error {
+	for _, entry := range os.Environ() {
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.TrimSuffix(parts[0], FileSuffix)
+		if ok, err := IsSecretName(name); err != nil {
+			return err
+		} else if !ok {
+			continue
+		}
+		if _, _, err := lookup(name); err != nil {
+			errorLog.Println("Warning:", err)
+		}
+	}
+	return nil
+}
+
+func secretNamePatterns() ([]*regexp.Regexp, error) {
+	result := SecretNamePatterns
+	for _, plain := range strings.Split(os.Getenv(EnvSecretPatterns), ",") {
+		if plain = strings.TrimSpace(plain); plain == "" {
+			continue
+		}
+		pattern, err := regexp.Compile(plain)
+		if err != nil {
+			return nil, fmt.Errorf("illegal pattern '%s' in %s: %v", plain, EnvSecretPatterns, err)
+		}
+		result = append(result[:len(result):len(result)], pattern)
+	}
+	return result, nil
+}
+
+// registerIfSecret registers value as secret if the variable of the given
+// name is a secret name.
+func registerIfSecret(name, value string) {
+	if ok, _ := IsSecretName(name); ok {
+		RegisterSecret(value)
+	}
+}
@@ -0,0 +1,122 @@
+package values
+
+import (
+	"net/url"
+	"time"
+)
+
+// Set reads several variables and records every missing or illegal value
+// instead of failing on the first one. After all variables are read Err
+// reports all problems together.
+//
+//	s := values.NewSet()
+//	registry := s.RequireString("REGISTRY")
+//	timeout := s.Duration("TIMEOUT", 5*time.Minute)
+//	if err := s.Err(); err != nil {
+//		return err
+//	}
+type Set struct {
+	errors Errors
+}
+
+func NewSet() *Set {
+	return &Set{}
+}
+
+// Err returns all recorded problems as Errors or nil if there are none.
+func (instance *Set) Err() error {
+	if len(instance.errors) == 0 {
+		return nil
+	}
+	return instance.errors
+}
+
+func (instance *Set) record(err error) {
+	if err != nil {
+		instance.errors = append(instance.errors, err)
+	}
+}
+
+func (instance *Set) String(name, def string) string {
+	v, err := String(name, def)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) RequireString(name string) string {
+	v, err := RequireString(name)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) Bool(name string, def bool) bool {
+	v, err := Bool(name, def)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) RequireBool(name string) bool {
+	v, err := RequireBool(name)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) Int(name string, def int) int {
+	v, err := Int(name, def)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) RequireInt(name string) int {
+	v, err := RequireInt(name)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) Duration(name string, def time.Duration) time.Duration {
+	v, err := Duration(name, def)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) RequireDuration(name string) time.Duration {
+	v, err := RequireDuration(name)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) Url(name string, def *url.URL) *url.URL {
+	v, err := Url(name, def)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) RequireUrl(name string) *url.URL {
+	v, err := RequireUrl(name)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) List(name, sep string, def []string) []string {
+	v, err := List(name, sep, def)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) RequireList(name, sep string) []string {
+	v, err := RequireList(name, sep)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) Enum(name, def string, allowed ...string) string {
+	v, err := Enum(name, def, allowed...)
+	instance.record(err)
+	return v
+}
+
+func (instance *Set) RequireEnum(name string, allowed ...string) string {
+	v, err := RequireEnum(name, allowed...)
+	instance.record(err)
+	return v
+}
@@ -0,0 +1,58 @@
+package values
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSetRecordsAllErrors(t *testing.T) {
+	defer setTestVariable(t, "MAGEPLUS_TEST_NAME", value("mageplus"))()
+	defer setTestVariable(t, "MAGEPLUS_TEST_MISSING", nil)()
+	defer setTestVariable(t, "MAGEPLUS_TEST_INT", value("many"))()
+	defer setTestVariable(t, "MAGEPLUS_TEST_TIMEOUT", value("1m"))()
+
+	s := NewSet()
+	if actual := s.String("MAGEPLUS_TEST_NAME", "default"); actual != "mageplus" {
+		t.Errorf("expected mageplus but got %s", actual)
+	}
+	if actual := s.RequireString("MAGEPLUS_TEST_MISSING"); actual != "" {
+		t.Errorf("expected empty string but got %s", actual)
+	}
+	if actual := s.Int("MAGEPLUS_TEST_INT", 3); actual != 3 {
+		t.Errorf("expected 3 but got %d", actual)
+	}
+	if actual := s.Duration("MAGEPLUS_TEST_TIMEOUT", time.Second); actual != time.Minute {
+		t.Errorf("expected %v but got %v", time.Minute, actual)
+	}
+
+	err := s.Err()
+	var errs Errors
+	if !errors.As(err, &errs) || len(errs) != 2 {
+		t.Fatalf("expected 2 errors but got %v", err)
+	}
+	var missing *MissingValueError
+	if !errors.As(errs[0], &missing) || missing.Name != "MAGEPLUS_TEST_MISSING" {
+		t.Errorf("expected MissingValueError of MAGEPLUS_TEST_MISSING but got %v", errs[0])
+	}
+	var invalid *InvalidValueError
+	if !errors.As(errs[1], &invalid) || invalid.Name != "MAGEPLUS_TEST_INT" {
+		t.Errorf("expected InvalidValueError of MAGEPLUS_TEST_INT but got %v", errs[1])
+	}
+	if !strings.HasPrefix(err.Error(), "2 problems with variables:\n\t") {
+		t.Errorf("unexpected message: %s", err)
+	}
+}
+
+func TestSetWithoutErrors(t *testing.T) {
+	defer setTestVariable(t, "MAGEPLUS_TEST_MISSING", nil)()
+
+	s := NewSet()
+	if actual := s.Bool("MAGEPLUS_TEST_MISSING", true); !actual {
+		t.Errorf("expected default value true but got %v", actual)
+	}
+	if err := s.Err(); err != nil {
+		t.Errorf("expected no error but got %v", err)
+	}
+}
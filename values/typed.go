@@ -0,0 +1,190 @@
+package values
+
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// errIllegalValue signals lookupTyped that the value is illegal without any
+// further details.
+var errIllegalValue = errors.New("illegal value")
+
+// Bool returns the value of the variable with the given name as bool (1, t,
+// true, 0, f, false, ...). If the variable is not present def is returned.
+func Bool(name string, def bool) (bool, error) {
+	return lookupBool(name, false, def)
+}
+
+// RequireBool is like Bool but fails if the variable is not present.
+func RequireBool(name string) (bool, error) {
+	return lookupBool(name, true, false)
+}
+
+// Int returns the value of the variable with the given name as int. If the
+// variable is not present def is returned.
+func Int(name string, def int) (int, error) {
+	return lookupInt(name, false, def)
+}
+
+// RequireInt is like Int but fails if the variable is not present.
+func RequireInt(name string) (int, error) {
+	return lookupInt(name, true, 0)
+}
+
+// Duration returns the value of the variable with the given name as
+// time.Duration (e.g. 5m30s). If the variable is not present def is
+// returned.
+func Duration(name string, def time.Duration) (time.Duration, error) {
+	return lookupDuration(name, false, def)
+}
+
+// RequireDuration is like Duration but fails if the variable is not present.
+func RequireDuration(name string) (time.Duration, error) {
+	return lookupDuration(name, true, 0)
+}
+
+// Url returns the value of the variable with the given name as absolute URL.
+// If the variable is not present def is returned.
+func Url(name string, def *url.URL) (*url.URL, error) {
+	return lookupUrl(name, false, def)
+}
+
+// RequireUrl is like Url but fails if the variable is not present.
+func RequireUrl(name string) (*url.URL, error) {
+	return lookupUrl(name, true, nil)
+}
+
+// List returns the value of the variable with the given name split by sep.
+// Surrounding spaces of every element are removed and empty elements are
+// skipped. If the variable is not present def is returned.
+func List(name, sep string, def []string) ([]string, error) {
+	return lookupList(name, false, sep, def)
+}
+
+// RequireList is like List but fails if the variable is not present.
+func RequireList(name, sep string) ([]string, error) {
+	return lookupList(name, true, sep, nil)
+}
+
+// Enum returns the value of the variable with the given name which has to
+// be one of allowed. If the variable is not present def is returned.
+func Enum(name, def string, allowed ...string) (string, error) {
+	return lookupEnum(name, false, def, allowed)
+}
+
+// RequireEnum is like Enum but fails if the variable is not present.
+func RequireEnum(name string, allowed ...string) (string, error) {
+	return lookupEnum(name, true, "", allowed)
+}
+
+// lookupTyped calls parse with the value of the variable with the given name
+// and converts its errors into InvalidValueError. If the variable is not
+// present parse is not called and, if required, MissingValueError is
+// returned.
+func lookupTyped(name string, required bool, expected string, parse func(string) error) (bool, error) {
+	v, ok, err := lookup(name)
+	if err == nil && !ok && required {
+		v, ok, err = promptForMissing(name)
+	}
+	if err != nil {
+		return false, err
+	} else if !ok {
+		if required {
+			return false, &MissingValueError{Name: name}
+		}
+		return false, nil
+	}
+	if err := parse(v); err == errIllegalValue {
+		return false, &InvalidValueError{Name: name, Value: v, Expected: expected}
+	} else if err != nil {
+		return false, &InvalidValueError{Name: name, Value: v, Expected: expected, Err: err}
+	}
+	return true, nil
+}
+
+func lookupBool(name string, required bool, def bool) (bool, error) {
+	var result bool
+	if ok, err := lookupTyped(name, required, "bool", func(v string) (err error) {
+		result, err = strconv.ParseBool(strings.TrimSpace(v))
+		return
+	}); err != nil || !ok {
+		return def, err
+	}
+	return result, nil
+}
+
+func lookupInt(name string, required bool, def int) (int, error) {
+	var result int
+	if ok, err := lookupTyped(name, required, "int", func(v string) (err error) {
+		result, err = strconv.Atoi(strings.TrimSpace(v))
+		return
+	}); err != nil || !ok {
+		return def, err
+	}
+	return result, nil
+}
+
+func lookupDuration(name string, required bool, def time.Duration) (time.Duration, error) {
+	var result time.Duration
+	if ok, err := lookupTyped(name, required, "duration (e.g. 5m30s)", func(v string) (err error) {
+		result, err = time.ParseDuration(strings.TrimSpace(v))
+		return
+	}); err != nil || !ok {
+		return def, err
+	}
+	return result, nil
+}
+
+func lookupUrl(name string, required bool, def *url.URL) (*url.URL, error) {
+	var result *url.URL
+	if ok, err := lookupTyped(name, required, "absolute URL", func(v string) (err error) {
+		if result, err = url.Parse(strings.TrimSpace(v)); err != nil {
+			return err
+		}
+		if !result.IsAbs() || result.Host == "" {
+			return errors.New("scheme and/or host missing")
+		}
+		return nil
+	}); err != nil || !ok {
+		return def, err
+	}
+	return result, nil
+}
+
+func lookupList(name string, required bool, sep string, def []string) ([]string, error) {
+	var result []string
+	if ok, err := lookupTyped(name, required, "list", func(v string) error {
+		if sep == "" {
+			return errors.New("empty separator")
+		}
+		for _, element := range strings.Split(v, sep) {
+			if element = strings.TrimSpace(element); element != "" {
+				result = append(result, element)
+			}
+		}
+		return nil
+	}); err != nil || !ok {
+		return def, err
+	}
+	return result, nil
+}
+
+func lookupEnum(name string, required bool, def string, allowed []string) (string, error) {
+	var result string
+	if ok, err := lookupTyped(name, required, "one of: "+strings.Join(allowed, ", "), func(v string) error {
+		v = strings.TrimSpace(v)
+		for _, candidate := range allowed {
+			if v == candidate {
+				result = v
+				return nil
+			}
+		}
+		return errIllegalValue
+	}); err != nil || !ok {
+		return def, err
+	}
+	return result, nil
+}
@@ -0,0 +1,169 @@
+package values
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const testVariable = "MAGEPLUS_TEST_VALUE"
+
+// setTestVariable sets the variable with the given name to v or removes it if v
+// is nil and returns a function which restores the previous state.
+func setTestVariable(t *testing.T, name string, v *string) func() {
+	old, present := os.LookupEnv(name)
+	var err error
+	if v == nil {
+		err = os.Unsetenv(name)
+	} else {
+		err = os.Setenv(name, *v)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func() {
+		if present {
+			_ = os.Setenv(name, old)
+		} else {
+			_ = os.Unsetenv(name)
+		}
+	}
+}
+
+func value(v string) *string {
+	return &v
+}
+
+func TestBool(t *testing.T) {
+	cases := []struct {
+		value    *string
+		expected bool
+		invalid  bool
+	}{
+		{nil, true, false},
+		{value("true"), true, false},
+		{value(" 1 "), true, false},
+		{value("f"), false, false},
+		{value("FALSE"), false, false},
+		{value("yes"), true, true},
+	}
+	for _, c := range cases {
+		restore := setTestVariable(t, testVariable, c.value)
+		actual, err := Bool(testVariable, true)
+		restore()
+		assertTyped(t, c.value, actual, c.expected, err, c.invalid)
+	}
+}
+
+func TestInt(t *testing.T) {
+	cases := []struct {
+		value    *string
+		expected int
+		invalid  bool
+	}{
+		{nil, 42, false},
+		{value("0"), 0, false},
+		{value(" -7 "), -7, false},
+		{value("1.5"), 42, true},
+		{value("many"), 42, true},
+	}
+	for _, c := range cases {
+		restore := setTestVariable(t, testVariable, c.value)
+		actual, err := Int(testVariable, 42)
+		restore()
+		assertTyped(t, c.value, actual, c.expected, err, c.invalid)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	cases := []struct {
+		value    *string
+		expected time.Duration
+		invalid  bool
+	}{
+		{nil, time.Minute, false},
+		{value("5m30s"), 5*time.Minute + 30*time.Second, false},
+		{value("250ms"), 250 * time.Millisecond, false},
+		{value("5"), time.Minute, true},
+	}
+	for _, c := range cases {
+		restore := setTestVariable(t, testVariable, c.value)
+		actual, err := Duration(testVariable, time.Minute)
+		restore()
+		assertTyped(t, c.value, actual, c.expected, err, c.invalid)
+	}
+}
+
+func TestList(t *testing.T) {
+	cases := []struct {
+		value    *string
+		sep      string
+		expected []string
+		invalid  bool
+	}{
+		{nil, ",", []string{"default"}, false},
+		{value("a,b"), ",", []string{"a", "b"}, false},
+		{value(" a , ,b, "), ",", []string{"a", "b"}, false},
+		{value("a b"), " ", []string{"a", "b"}, false},
+		{value(""), ",", nil, false},
+		{value("a,b"), "", []string{"default"}, true},
+	}
+	for _, c := range cases {
+		restore := setTestVariable(t, testVariable, c.value)
+		actual, err := List(testVariable, c.sep, []string{"default"})
+		restore()
+		assertTyped(t, c.value, actual, c.expected, err, c.invalid)
+	}
+}
+
+func TestRequireMissing(t *testing.T) {
+	defer setTestVariable(t, testVariable, nil)()
+
+	for name, require := range map[string]func() error{
+		"RequireString":   func() (err error) { _, err = RequireString(testVariable); return },
+		"RequireBool":     func() (err error) { _, err = RequireBool(testVariable); return },
+		"RequireInt":      func() (err error) { _, err = RequireInt(testVariable); return },
+		"RequireDuration": func() (err error) { _, err = RequireDuration(testVariable); return },
+		"RequireList":     func() (err error) { _, err = RequireList(testVariable, ","); return },
+	} {
+		var missing *MissingValueError
+		if err := require(); !errors.As(err, &missing) || missing.Name != testVariable {
+			t.Errorf("%s: expected MissingValueError of %s but got %v", name, testVariable, err)
+		}
+	}
+}
+
+func TestInvalidValueError(t *testing.T) {
+	defer setTestVariable(t, testVariable, value("many"))()
+
+	_, err := RequireInt(testVariable)
+	var invalid *InvalidValueError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected InvalidValueError but got %v", err)
+	}
+	if invalid.Name != testVariable || invalid.Value != "many" || invalid.Expected != "int" || invalid.Err == nil {
+		t.Errorf("unexpected content of %#v", invalid)
+	}
+	if expected := `variable 'MAGEPLUS_TEST_VALUE' has illegal value "many"; expected int: ` + invalid.Err.Error(); err.Error() != expected {
+		t.Errorf("expected %q but got %q", expected, err.Error())
+	}
+}
+
+func assertTyped(t *testing.T, v *string, actual, expected interface{}, err error, invalid bool) {
+	t.Helper()
+	name := "<not present>"
+	if v != nil {
+		name = *v
+	}
+	var invalidErr *InvalidValueError
+	if invalid && !errors.As(err, &invalidErr) {
+		t.Errorf("%s: expected InvalidValueError but got %v", name, err)
+	} else if !invalid && err != nil {
+		t.Errorf("%s: expected no error but got %v", name, err)
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("%s: expected %v but got %v", name, expected, actual)
+	}
+}
@@ -1,34 +1,100 @@
 package values
 
 import (
+	"fmt"
+	"io/ioutil"
 	"os"
 	"strings"
 )
 
+// FileSuffix is appended to the name of a variable to point to a file which
+// contains its value, e.g. GITLAB_TOKEN_FILE=/run/secrets/gitlab-token.
+const FileSuffix = "_FILE"
+
 func RequireValue(name string, replacements ...string) (result string) {
-	if v, ok := os.LookupEnv(name); ok {
-		result = v
-	} else {
-		errorLog.Fatalf("Error: required variable '%s' not present", name)
+	v, err := RequireString(name)
+	if err == nil {
+		v, err = replace(v, replacements)
 	}
-	return replace(result, replacements)
+	if err != nil {
+		errorLog.Fatalln("Error:", err)
+	}
+	return v
 }
 
+// Value returns the value of the variable with the given name or def if it
+// is not present. It exits the process if the value could not be read (see
+// lookup); use String to handle this error.
 func Value(name, def string, replacements ...string) (result string) {
+	result, err := String(name, def)
+	if err == nil {
+		result, err = replace(result, replacements)
+	}
+	if err != nil {
+		errorLog.Fatalln("Error:", err)
+	}
+	return result
+}
+
+// String returns the value of the variable with the given name. If the
+// variable is not present def is returned.
+func String(name, def string) (string, error) {
+	if v, ok, err := lookup(name); err != nil {
+		return "", err
+	} else if ok {
+		return v, nil
+	}
+	return def, nil
+}
+
+// RequireString returns the value of the variable with the given name or
+// an error if it is not present.
+func RequireString(name string) (string, error) {
+	if v, ok, err := lookup(name); err != nil {
+		return "", err
+	} else if ok {
+		return v, nil
+	}
+	if v, ok, err := promptForMissing(name); err != nil {
+		return "", err
+	} else if ok {
+		return v, nil
+	}
+	return "", &MissingValueError{Name: name}
+}
+
+// lookup returns the value of the environment variable with the given name.
+// If it is not present but <name>_FILE is, the value is read from the file
+// this variable points to (like Docker and Kubernetes secrets are mounted).
+// Trailing newlines of the file are removed. Values of secret names (see
+// IsSecretName) are registered as secrets.
+func lookup(name string) (string, bool, error) {
 	if v, ok := os.LookupEnv(name); ok {
-		result = v
-	} else {
-		result = def
+		registerIfSecret(name, v)
+		return v, true, nil
+	}
+	file, ok := os.LookupEnv(name + FileSuffix)
+	if !ok {
+		return "", false, nil
+	}
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", false, fmt.Errorf("cannot read variable '%s' from file '%s' (set by %s%s): %v", name, file, name, FileSuffix, err)
 	}
-	return replace(result, replacements)
+	v := strings.TrimRight(string(b), "\r\n")
+	registerIfSecret(name, v)
+	return v, true, nil
 }
 
-func replace(value string, replacements []string) string {
+// replace replaces every %key% inside of value by its replacement. The
+// replacements are pairs of key and replacement; see Render for a more
+// powerful alternative.
+func replace(value string, replacements []string) (string, error) {
 	if len(replacements)%2 != 0 {
-		panic("Number of replicate parameters needs to be /2.")
+		return "", fmt.Errorf("replacements have to be pairs of key and replacement, but got %d values: %q", len(replacements), replacements)
 	}
 	for i := 0; i < len(replacements); i += 2 {
 		value = strings.ReplaceAll(value, "%"+replacements[i]+"%", replacements[i+1])
 	}
-	return value
+	return value, nil
 }
@@ -0,0 +1,39 @@
+package wrapper
+
+import (
+	"fmt"
+	"github.com/echocat/mageplus/release"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// BinariesCacheDir returns the directory where the wrapper scripts inside of
+// targetDir cache the downloaded mageplus binaries.
+func BinariesCacheDir(targetDir string) (string, error) {
+	if properties, ok, err := LoadProperties(targetDir); err != nil {
+		return "", err
+	} else if ok && properties.CacheDir != "" {
+		return properties.CacheDir, nil
+	}
+	if runtime.GOOS == "windows" {
+		if v, ok := os.LookupEnv("LOCALAPPDATA"); ok {
+			return filepath.Join(v, "mageplus", "binaries"), nil
+		}
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot determine binaries cache directory: %v", err)
+	}
+	return filepath.Join(homeDir, ".mageplus", "binaries"), nil
+}
+
+// CachedBinaryName returns the name of the binary for the given version and
+// platform inside of BinariesCacheDir.
+func CachedBinaryName(version, goos, goarch string) string {
+	ext := ""
+	if goos == "windows" {
+		ext = ".exe"
+	}
+	return fmt.Sprintf("mageplus-%s-%s-%s%s", release.OsName(goos), release.ArchName(goarch), release.NormalizeVersion(version), ext)
+}
@@ -0,0 +1,89 @@
+package wrapper
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Drift describes a wrapper file which differs from what Write would create.
+type Drift struct {
+	File   string
+	Reason string
+	Diff   string // Unified diff between the file on disk and the expected content
+}
+
+func (instance Drift) String() string {
+	if instance.Diff == "" {
+		return fmt.Sprintf("%s: %s", instance.File, instance.Reason)
+	}
+	return fmt.Sprintf("%s: %s\n%s", instance.File, instance.Reason, strings.TrimSuffix(instance.Diff, "\n"))
+}
+
+// Check compares the wrapper files inside of targetDir with the files Write
+// would create for the given options. It returns one Drift for each file
+// which is missing or differs.
+func Check(targetDir string, options Options) ([]Drift, error) {
+	ensureScripts()
+	properties, err := propertiesFor(targetDir, options)
+	if err != nil {
+		return nil, err
+	}
+
+	type candidate struct {
+		name    string
+		content func() ([]byte, error)
+	}
+	candidates := []candidate{
+		{PropertiesFile, func() ([]byte, error) { return properties.Bytes(), nil }},
+	}
+	for _, flavour := range flavoursOf(options) {
+		candidates = append(candidates, candidate{flavour.FileName(), flavour.content})
+	}
+
+	var result []Drift
+	for _, candidate := range candidates {
+		expected, err := candidate.content()
+		if err != nil {
+			return nil, err
+		}
+		drift, err := checkFile(filepath.Join(targetDir, candidate.name), expected)
+		if err != nil {
+			return nil, err
+		}
+		if drift != nil {
+			result = append(result, *drift)
+		}
+	}
+	return result, nil
+}
+
+func checkFile(target string, expected []byte) (*Drift, error) {
+	actual, err := ioutil.ReadFile(target)
+	if os.IsNotExist(err) {
+		return &Drift{File: target, Reason: "missing"}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot read '%s': %v", target, err)
+	}
+	if bytes.Equal(actual, expected) {
+		return nil, nil
+	}
+
+	diff := unifiedDiff(target+" (actual)", target+" (expected)", toLines(actual), toLines(expected))
+	if diff == "" {
+		return &Drift{File: target, Reason: "line endings differ"}, nil
+	}
+	return &Drift{File: target, Reason: "differs from the generated wrapper", Diff: diff}, nil
+}
+
+func toLines(content []byte) []string {
+	s := strings.ReplaceAll(string(content), "\r\n", "\n")
+	s = strings.TrimSuffix(s, "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
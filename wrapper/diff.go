@@ -0,0 +1,135 @@
+package wrapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+const diffContextLines = 3
+
+type diffOp struct {
+	kind  byte // ' ', '-' or '+'
+	line  string
+	aLine int // 1-based line number in a of this (or the next) line
+	bLine int // 1-based line number in b of this (or the next) line
+}
+
+// unifiedDiff returns the difference of a and b in unified diff format or an
+// empty string if both are equal.
+func unifiedDiff(aName, bName string, a, b []string) string {
+	ops := diffOps(a, b)
+
+	changed := false
+	for _, op := range ops {
+		if op.kind != ' ' {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return ""
+	}
+
+	buf := new(strings.Builder)
+	_, _ = fmt.Fprintf(buf, "--- %s\n+++ %s\n", aName, bName)
+	for start := 0; start < len(ops); {
+		// Find the next change...
+		first := start
+		for first < len(ops) && ops[first].kind == ' ' {
+			first++
+		}
+		if first == len(ops) {
+			break
+		}
+		// ... and extend the hunk as long as the changes are close together.
+		last := first
+		for i := first; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				last = i
+			} else if i-last > 2*diffContextLines {
+				break
+			}
+		}
+		from := maxInt(first-diffContextLines, start)
+		to := minInt(last+diffContextLines+1, len(ops))
+		writeHunk(buf, ops[from:to])
+		start = to
+	}
+	return buf.String()
+}
+
+func writeHunk(buf *strings.Builder, ops []diffOp) {
+	aStart, bStart := ops[0].aLine, ops[0].bLine
+	aLen, bLen := 0, 0
+	for _, op := range ops {
+		if op.kind != '+' {
+			aLen++
+		}
+		if op.kind != '-' {
+			bLen++
+		}
+	}
+	if aLen == 0 {
+		aStart--
+	}
+	if bLen == 0 {
+		bStart--
+	}
+	_, _ = fmt.Fprintf(buf, "@@ -%d,%d +%d,%d @@\n", aStart, aLen, bStart, bLen)
+	for _, op := range ops {
+		buf.WriteByte(op.kind)
+		buf.WriteString(op.line)
+		buf.WriteByte('\n')
+	}
+}
+
+// diffOps calculates the edit script from a to b based on the longest common
+// subsequence of both. The wrapper scripts are small enough that the
+// quadratic effort does not matter.
+func diffOps(a, b []string) []diffOp {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = maxInt(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var result []diffOp
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			result = append(result, diffOp{' ', a[i], i + 1, j + 1})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			result = append(result, diffOp{'-', a[i], i + 1, j + 1})
+			i++
+		default:
+			result = append(result, diffOp{'+', b[j], i + 1, j + 1})
+			j++
+		}
+	}
+	return result
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,112 @@
+package wrapper
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Flavour is one kind of wrapper script which can be created by WriteWith.
+type Flavour string
+
+const (
+	FlavourSh         Flavour = "sh"  // mageplusw for UNIX like systems
+	FlavourCmd        Flavour = "cmd" // mageplusw.cmd for the Windows command prompt
+	FlavourPowerShell Flavour = "ps1" // mageplusw.ps1 for PowerShell on any system
+)
+
+// AllFlavours contains every supported Flavour. It is used if no flavours
+// are selected explicitly.
+var AllFlavours = []Flavour{FlavourSh, FlavourCmd, FlavourPowerShell}
+
+// ParseFlavours parses a comma separated list of flavours like "sh,ps1". An
+// empty string results in AllFlavours.
+func ParseFlavours(plain string) ([]Flavour, error) {
+	if strings.TrimSpace(plain) == "" {
+		return AllFlavours, nil
+	}
+	var result []Flavour
+	for _, part := range strings.Split(plain, ",") {
+		candidate := Flavour(strings.TrimSpace(part))
+		if !candidate.isValid() {
+			return nil, fmt.Errorf("unsupported wrapper flavour %q; supported are: %s", part, joinFlavours(AllFlavours))
+		}
+		if !containsFlavour(result, candidate) {
+			result = append(result, candidate)
+		}
+	}
+	return result, nil
+}
+
+// ExistingFlavours returns the flavours of which a script already exists
+// inside of targetDir.
+func ExistingFlavours(targetDir string) ([]Flavour, error) {
+	var result []Flavour
+	for _, candidate := range AllFlavours {
+		if ok, err := exists(filepath.Join(targetDir, candidate.FileName())); err != nil {
+			return nil, err
+		} else if ok {
+			result = append(result, candidate)
+		}
+	}
+	return result, nil
+}
+
+// FileName returns the name of the script of this flavour.
+func (instance Flavour) FileName() string {
+	switch instance {
+	case FlavourCmd:
+		return "mageplusw.cmd"
+	case FlavourPowerShell:
+		return "mageplusw.ps1"
+	default:
+		return "mageplusw"
+	}
+}
+
+func (instance Flavour) isValid() bool {
+	return containsFlavour(AllFlavours, instance)
+}
+
+// script returns the base64 encoded content of the script of this flavour,
+// whether it requires CRLF line endings and its file permissions.
+func (instance Flavour) script() (string, bool, os.FileMode) {
+	switch instance {
+	case FlavourCmd:
+		return windowsScript, true, 0644
+	case FlavourPowerShell:
+		return powershellScript, true, 0644
+	default:
+		return unixScript, false, 0755
+	}
+}
+
+func (instance Flavour) content() ([]byte, error) {
+	raw, crlf, _ := instance.script()
+	return prepareContent(raw, crlf)
+}
+
+func flavoursOf(options Options) []Flavour {
+	if len(options.Flavours) == 0 {
+		return AllFlavours
+	}
+	return options.Flavours
+}
+
+func containsFlavour(haystack []Flavour, needle Flavour) bool {
+	for _, candidate := range haystack {
+		if candidate == needle {
+			return true
+		}
+	}
+	return false
+}
+
+func joinFlavours(flavours []Flavour) string {
+	plain := make([]string, len(flavours))
+	for i, flavour := range flavours {
+		plain[i] = string(flavour)
+	}
+	return strings.Join(plain, ", ")
+}
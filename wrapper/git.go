@@ -0,0 +1,140 @@
+package wrapper
+
+import (
+	"bytes"
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"io/ioutil"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const gitAttributesFile = ".gitattributes"
+
+var (
+	infoLog = log.New(os.Stderr, "", 0)
+
+	// gitEols contains the line endings git has to use for the script of
+	// each flavour, regardless of the core.autocrlf setting of the user.
+	gitEols = map[Flavour]string{
+		FlavourSh:         "lf",
+		FlavourCmd:        "crlf",
+		FlavourPowerShell: "crlf",
+	}
+)
+
+// prepareGit ensures that the written scripts of the given flavours can be
+// committed as they are if targetDir is part of a git work tree: The line
+// endings are fixed using .gitattributes and mageplusw is marked as
+// executable inside of the index, which is not possible on Windows using
+// the file system. If targetDir is not part of a git work tree nothing
+// happens.
+func prepareGit(targetDir string, flavours []Flavour) error {
+	if !isGitWorkTree(targetDir) {
+		return nil
+	}
+	if err := ensureGitAttributes(targetDir, flavours); err != nil {
+		return err
+	}
+	if containsFlavour(flavours, FlavourSh) {
+		if err := ensureExecutableInGitIndex(targetDir, FlavourSh.FileName()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isGitWorkTree(dir string) bool {
+	if _, err := exec.LookPath("git"); err != nil {
+		return false
+	}
+	out, err := git(dir, "rev-parse", "--is-inside-work-tree")
+	return err == nil && out == "true"
+}
+
+// ensureGitAttributes adds an eol entry for each script of the given flavours
+// to the .gitattributes of targetDir. Existing entries of the scripts are
+// respected as they are.
+func ensureGitAttributes(targetDir string, flavours []Flavour) error {
+	file := filepath.Join(targetDir, gitAttributesFile)
+	content, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot read '%s': %v", file, err)
+	}
+
+	present := map[string]bool{}
+	for _, line := range toLines(content) {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			present[fields[0]] = true
+		}
+	}
+
+	buf := bytes.NewBuffer(content)
+	var added []string
+	for _, flavour := range flavours {
+		// Unanchored entries (matching in all directories) are respected too.
+		pattern := "/" + flavour.FileName()
+		if present[pattern] || present[flavour.FileName()] {
+			continue
+		}
+		if len(added) == 0 && buf.Len() > 0 && !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+			buf.WriteString("\n")
+		}
+		_, _ = fmt.Fprintf(buf, "%s eol=%s\n", pattern, gitEols[flavour])
+		added = append(added, flavour.FileName())
+	}
+	if len(added) == 0 {
+		return nil
+	}
+
+	if err := mio.WriteFileAtomic(file, buf.Bytes(), 0644); err != nil {
+		return err
+	}
+	infoLog.Printf("Line endings of %s added to %s", strings.Join(added, ", "), file)
+	return nil
+}
+
+// ensureExecutableInGitIndex marks the given file as executable inside of the
+// git index. Files which are not tracked yet are not added to the index, but
+// a hint how to add them as executable is printed.
+func ensureExecutableInGitIndex(targetDir, name string) error {
+	file := filepath.Join(targetDir, name)
+	if _, err := git(targetDir, "check-ignore", "-q", "--", name); err == nil {
+		// Ignored files could not be part of the index.
+		return nil
+	}
+
+	stage, err := git(targetDir, "ls-files", "--stage", "--", name)
+	if err != nil {
+		return err
+	}
+	switch {
+	case stage == "":
+		infoLog.Printf("%s is not tracked by git yet; add it as executable using: git add --chmod=+x %s", file, name)
+	case strings.HasPrefix(stage, "100755 "):
+	default:
+		if _, err := git(targetDir, "update-index", "--chmod=+x", "--", name); err != nil {
+			return err
+		}
+		infoLog.Printf("Executable flag of %s set in the git index", file)
+	}
+	return nil
+}
+
+func git(dir string, args ...string) (string, error) {
+	stdout, stderr := new(bytes.Buffer), new(bytes.Buffer)
+	c := exec.Command("git", args...)
+	c.Dir = dir
+	c.Stdout = stdout
+	c.Stderr = stderr
+	if err := c.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("cannot execute 'git %s': %v: %s", strings.Join(args, " "), err, msg)
+		}
+		return "", fmt.Errorf("cannot execute 'git %s': %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
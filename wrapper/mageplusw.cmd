@@ -4,6 +4,8 @@ REM ############################################################################
 REM ##                                                                          ##
 REM ##  mageplus bootstrap wrapper for Windows systems                          ##
 REM ##                                                                          ##
+REM ##  The configuration is located in .mageplus\wrapper.properties            ##
+REM ##                                                                          ##
 REM ##############################################################################
 REM ##  DO NOT EDIT!!                                                           ##
 REM ##############################################################################
@@ -17,15 +19,29 @@ IF "%PROCESSOR_ARCHITECTURE%" == "AMD64" (
     SET arch=64bit
 )
 
-IF NOT EXIST "%dirName%\mageplusw" (
-    CALL :fatal This mageplus wrapper was not initiated correctly. Try download mageplus binary and run: mageplus -wrapper
+SET propertiesFile=%dirName%.mageplus\wrapper.properties
+IF NOT EXIST "%propertiesFile%" (
+    CALL :fatal This mageplus wrapper was not initiated correctly: %propertiesFile% is missing. Try download mageplus binary and run: mageplus -wrapper
     EXIT /b 1
 )
-SET findCmd=FINDSTR /B "version=" "%dirName%\mageplusw"
-FOR /f %%i IN ('%findCmd%') DO SET versionLine=%%i
-SET version=%versionLine:~9,-1%
-
-SET binariesCacheDir=%LOCALAPPDATA%\mageplus\binaries
+FOR /F "usebackq eol=# tokens=1,* delims==" %%a IN ("%propertiesFile%") DO SET "property.%%a=%%b"
+SET version=%property.version%
+IF "%version%" == "" (
+    CALL :fatal There is no version configured in %propertiesFile%.
+    EXIT /b 1
+)
+SET goVersion=%property.goVersion%
+SET releasesUrl=%property.releasesUrl%
+IF DEFINED MAGEPLUSW_BASE_URL (
+    SET releasesUrl=%MAGEPLUSW_BASE_URL%
+)
+IF "%releasesUrl%" == "" (
+    SET releasesUrl=https://github.com/echocat/mageplus/releases
+)
+SET binariesCacheDir=%property.cacheDir%
+IF "%binariesCacheDir%" == "" (
+    SET binariesCacheDir=%LOCALAPPDATA%\mageplus\binaries
+)
 IF NOT EXIST "%binariesCacheDir%" (
     md "%binariesCacheDir%"
 )
@@ -45,20 +61,61 @@ IF NOT EXIST "%binary%" (
 )
 
 IF "%ERRORLEVEL%" == "0" (
-    "%binary%" %*
+    IF "%goVersion%" == "" (
+        "%binary%" %*
+    ) ELSE (
+        "%binary%" -go "%goVersion%" %*
+    )
 )
 EXIT /b %ERRORLEVEL%
 GOTO :eofSuccess
 
 :doDownload
     SETLOCAL
-    SET binaryDownloadUrl=https://github.com/echocat/mageplus/releases/download/v%version%/mageplus_%version%_%os%-%arch%%downloadExt%
-    CALL :info Downloading %binaryDownloadUrl%...
+    SET binaryDownloadUrl=%releasesUrl%/download/v%version%/mageplus_%version%_%os%-%arch%%downloadExt%
+    IF DEFINED MAGEPLUSW_ARCHIVE (
+        SET binaryDownloadUrl=%MAGEPLUSW_ARCHIVE%
+        CALL :info Installing %MAGEPLUSW_ARCHIVE%...
+    ) ELSE (
+        CALL :info Downloading %binaryDownloadUrl%...
+    )
+
+    CALL SET expectedChecksum=%%property.checksum.%os%-%arch%%%
+    IF "%expectedChecksum%" == "" (
+        CALL :fatal There is no checksum for %os%-%arch% configured in %propertiesFile%. Try download mageplus binary and run: mageplus -wrapper
+        EXIT /b 1
+    )
 
     SET tmpDirectory=%binary%.%RANDOM%.tmp
-    PowerShell -Command "New-Item -Path '%tmpDirectory%' -Type Directory -Force | Out-Null; (New-Object Net.WebClient).DownloadFile('%binaryDownloadUrl%','%tmpDirectory%\tmp.zip'); Expand-Archive '%tmpDirectory%\tmp.zip' -DestinationPath '%tmpDirectory%'; Move-Item '%tmpDirectory%\mageplus.exe' '%binary%'; Remove-Item '%tmpDirectory%' -Recurse -Force"
+    IF DEFINED MAGEPLUSW_ARCHIVE (
+        PowerShell -Command "New-Item -Path '%tmpDirectory%' -Type Directory -Force | Out-Null; Copy-Item '%MAGEPLUSW_ARCHIVE%' '%tmpDirectory%\tmp.zip'"
+    ) ELSE (
+        PowerShell -Command "New-Item -Path '%tmpDirectory%' -Type Directory -Force | Out-Null; $client = New-Object Net.WebClient; if ($env:MAGEPLUSW_TOKEN) { $client.Headers.Add('Authorization', 'Bearer ' + $env:MAGEPLUSW_TOKEN) } elseif ($env:MAGEPLUSW_USERNAME) { $client.Headers.Add('Authorization', 'Basic ' + [Convert]::ToBase64String([Text.Encoding]::UTF8.GetBytes($env:MAGEPLUSW_USERNAME + ':' + $env:MAGEPLUSW_PASSWORD))) }; $client.DownloadFile('%binaryDownloadUrl%','%tmpDirectory%\tmp.zip')"
+    )
     IF "%ERRORLEVEL%" NEQ "0" (
         CALL :fatal Was not able to download binary from %binaryDownloadUrl%. See above.
+        EXIT /b 1
+    )
+
+    REM Output of certutil which does not contain a checksum (including the
+    REM one of failures) always contains a colon.
+    SET actualChecksum=
+    FOR /F "delims=" %%h IN ('certutil -hashfile "%tmpDirectory%\tmp.zip" SHA256 ^| findstr /v ":"') DO SET "actualChecksum=%%h"
+    IF NOT DEFINED actualChecksum (
+        RMDIR /S /Q "%tmpDirectory%"
+        CALL :fatal Was not able to calculate checksum of %tmpDirectory%\tmp.zip.
+        EXIT /b 1
+    )
+    SET "actualChecksum=%actualChecksum: =%"
+    IF /I NOT "%actualChecksum%" == "%expectedChecksum%" (
+        RMDIR /S /Q "%tmpDirectory%"
+        CALL :fatal Checksum mismatch of %binaryDownloadUrl%: expected %expectedChecksum% but got %actualChecksum%.
+        EXIT /b 1
+    )
+
+    PowerShell -Command "Expand-Archive '%tmpDirectory%\tmp.zip' -DestinationPath '%tmpDirectory%'; Move-Item '%tmpDirectory%\mageplus.exe' '%binary%'; Remove-Item '%tmpDirectory%' -Recurse -Force"
+    IF "%ERRORLEVEL%" NEQ "0" (
+        CALL :fatal Was not able to extract binary from %binaryDownloadUrl%. See above.
     )
     ENDLOCAL
     EXIT /b %ERRORLEVEL%
@@ -0,0 +1,193 @@
+##############################################################################
+##                                                                          ##
+##  mageplus bootstrap wrapper for PowerShell                               ##
+##                                                                          ##
+##  The configuration is located in .mageplus/wrapper.properties            ##
+##                                                                          ##
+##############################################################################
+##  DO NOT EDIT!!                                                           ##
+##############################################################################
+$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'
+
+function Fatal([string] $message) {
+    [Console]::Error.WriteLine("FATAL: $message")
+    exit 1
+}
+
+function Info([string] $message) {
+    [Console]::Error.WriteLine("INFO: $message")
+}
+
+function Invoke-Download([string] $url, [string] $target) {
+    $headers = @{}
+    if ($env:MAGEPLUSW_TOKEN) {
+        $headers['Authorization'] = "Bearer $($env:MAGEPLUSW_TOKEN)"
+    } elseif ($env:MAGEPLUSW_USERNAME) {
+        $credentials = "$($env:MAGEPLUSW_USERNAME):$($env:MAGEPLUSW_PASSWORD)"
+        $headers['Authorization'] = 'Basic ' + [Convert]::ToBase64String([Text.Encoding]::UTF8.GetBytes($credentials))
+    }
+    Invoke-WebRequest -Uri $url -OutFile $target -Headers $headers -UseBasicParsing
+}
+
+function Install-Binary {
+    $expectedChecksum = $properties["checksum.$os-$arch"]
+    if (-not $expectedChecksum) {
+        Fatal "There is no checksum for $os-$arch configured in $propertiesFile. Try download mageplus binary and run: mageplus -wrapper"
+    }
+
+    $binaryDownloadUrl = "$releasesUrl/download/v$version/mageplus_$($version)_$os-$arch$downloadExt"
+    $tmpDirectory = "$binary.$(Get-Random).tmp"
+    $tmpArchive = Join-Path $tmpDirectory "tmp$downloadExt"
+
+    try {
+        New-Item -Path $tmpDirectory -ItemType Directory -Force | Out-Null
+    } catch {
+        Fatal "Cannot create cache directory for storing binaries: $_"
+    }
+
+    try {
+        if ($env:MAGEPLUSW_ARCHIVE) {
+            $binaryDownloadUrl = $env:MAGEPLUSW_ARCHIVE
+            Info "Installing $binaryDownloadUrl..."
+            Copy-Item -Path $env:MAGEPLUSW_ARCHIVE -Destination $tmpArchive
+        } else {
+            Info "Downloading $binaryDownloadUrl..."
+            Invoke-Download $binaryDownloadUrl $tmpArchive
+        }
+    } catch {
+        Remove-Item -Path $tmpDirectory -Recurse -Force -ErrorAction SilentlyContinue
+        Fatal "Was not able to download binary from $($binaryDownloadUrl): $_"
+    }
+
+    $actualChecksum = (Get-FileHash -Algorithm SHA256 -Path $tmpArchive).Hash.ToLower()
+    if ($actualChecksum -ne $expectedChecksum.ToLower()) {
+        Remove-Item -Path $tmpDirectory -Recurse -Force -ErrorAction SilentlyContinue
+        Fatal "Checksum mismatch of $($binaryDownloadUrl): expected $expectedChecksum but got $actualChecksum."
+    }
+
+    try {
+        if ($downloadExt -eq '.zip') {
+            Expand-Archive -Path $tmpArchive -DestinationPath $tmpDirectory -Force
+        } else {
+            tar -xzf $tmpArchive -C $tmpDirectory
+            if ($LASTEXITCODE -ne 0) {
+                throw "tar exited with $LASTEXITCODE"
+            }
+        }
+        Move-Item -Path (Join-Path $tmpDirectory "mageplus$ext") -Destination $binary -Force
+        if ($os -ne 'Windows') {
+            chmod +x $binary
+        }
+    } catch {
+        Fatal "Was not able to extract $($tmpArchive): $_"
+    } finally {
+        Remove-Item -Path $tmpDirectory -Recurse -Force -ErrorAction SilentlyContinue
+    }
+}
+
+$propertiesFile = Join-Path $PSScriptRoot '.mageplus/wrapper.properties'
+if (-not (Test-Path -Path $propertiesFile -PathType Leaf)) {
+    Fatal "This mageplus wrapper was not initiated correctly: $propertiesFile is missing. Try download mageplus binary and run: mageplus -wrapper"
+}
+$properties = @{}
+foreach ($line in Get-Content -Path $propertiesFile) {
+    $line = $line.Trim()
+    if ($line -eq '' -or $line.StartsWith('#')) {
+        continue
+    }
+    $parts = $line.Split('=', 2)
+    if ($parts.Count -eq 2) {
+        $properties[$parts[0].Trim()] = $parts[1].Trim()
+    }
+}
+
+$version = $properties['version']
+if (-not $version) {
+    Fatal "There is no version configured in $propertiesFile."
+}
+$goVersion = $properties['goVersion']
+$releasesUrl = $properties['releasesUrl']
+if ($env:MAGEPLUSW_BASE_URL) {
+    $releasesUrl = $env:MAGEPLUSW_BASE_URL
+} elseif (-not $releasesUrl) {
+    $releasesUrl = 'https://github.com/echocat/mageplus/releases'
+}
+
+if ($IsLinux) {
+    $os = 'Linux'
+} elseif ($IsMacOS) {
+    $os = 'macOS'
+} else {
+    $os = 'Windows'
+}
+
+if ($env:PROCESSOR_ARCHITEW6432) {
+    $plainArch = $env:PROCESSOR_ARCHITEW6432
+} elseif ($env:PROCESSOR_ARCHITECTURE) {
+    $plainArch = $env:PROCESSOR_ARCHITECTURE
+} else {
+    $plainArch = uname -m
+}
+switch -Wildcard ($plainArch) {
+    'AMD64'   { $arch = '64bit' }
+    'x86_64*' { $arch = '64bit' }
+    'x86'     { $arch = '32bit' }
+    'i386*'   { $arch = '32bit' }
+    'ARM64'   { $arch = 'ARM64' }
+    'arm64*'  { $arch = 'ARM64' }
+    'aarch64' { $arch = 'ARM64' }
+    'arm*'    { if (-not $arch) { $arch = 'ARM' } }
+    default   { Fatal "Unsupported architecture: $plainArch" }
+}
+
+if ($os -eq 'Windows') {
+    $ext = '.exe'
+    $downloadExt = '.zip'
+} else {
+    $ext = ''
+    $downloadExt = '.tar.gz'
+}
+
+$binariesCacheDir = $properties['cacheDir']
+if (-not $binariesCacheDir) {
+    if ($os -eq 'Windows') {
+        $binariesCacheDir = Join-Path $env:LOCALAPPDATA 'mageplus\binaries'
+    } else {
+        $binariesCacheDir = Join-Path $HOME '.mageplus/binaries'
+    }
+}
+$binary = Join-Path $binariesCacheDir "mageplus-$os-$arch-$version$ext"
+
+if ($env:MAGEPLUSW_IGNORE_DOCKER_IMAGE_MISMATCH -ne 'yes') {
+    if (Test-Path -Path '/usr/lib/mageplus/docker-version' -PathType Leaf) {
+        $dockerVersion = (Get-Content -Path '/usr/lib/mageplus/docker-version' -Raw).Trim()
+        if ($dockerVersion -ne $version) {
+            if (Test-Path -Path '/usr/lib/mageplus/docker-image' -PathType Leaf) {
+                $dockerImage = (Get-Content -Path '/usr/lib/mageplus/docker-image' -Raw).Trim()
+            } else {
+                $dockerImage = 'echocat/mageplus'
+            }
+            Fatal ("You're are using mageplusw with version $version inside of a mageplus docker image with version $dockerVersion." +
+                " This could lead to unexpected behaviors. We recommend to align both versions together by either:" +
+                "`n`t1.) Change $propertiesFile to: version=$dockerVersion" +
+                "`n`t2.) ... or set the used image to: $($dockerImage):$version" +
+                "`nYou can suppress this error by set MAGEPLUSW_IGNORE_DOCKER_IMAGE_MISMATCH=yes")
+        }
+    }
+}
+
+$upToDate = $false
+if (Test-Path -Path $binary -PathType Leaf) {
+    $upToDate = [bool](& $binary -version 2>&1 | Select-String -SimpleMatch $version)
+}
+if (-not $upToDate) {
+    Install-Binary
+}
+
+if ($goVersion) {
+    & $binary -go $goVersion @args
+} else {
+    & $binary @args
+}
+exit $LASTEXITCODE
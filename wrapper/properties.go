@@ -0,0 +1,179 @@
+package wrapper
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"github.com/echocat/mageplus/release"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const checksumPropertyPrefix = "checksum."
+
+// PropertiesFile is the location of the wrapper configuration relative to the
+// directory which contains the wrapper scripts.
+var PropertiesFile = filepath.Join(".mageplus", "wrapper.properties")
+
+// Properties is the configuration of the wrapper scripts which is stored in
+// PropertiesFile. Keeping it outside of the scripts ensures that an upgrade
+// of mageplus only changes this file instead of the executable scripts.
+type Properties struct {
+	Version     string            // Version of mageplus to use
+	GoVersion   string            // Version of the golang SDK mageplus should use; empty means default
+	ReleasesUrl string            // Base URL where the releases of mageplus are downloaded from
+	CacheDir    string            // Directory where the downloaded binaries are cached; empty means default
+	Checksums   map[string]string // SHA-256 of the release artifacts by platform (<os>-<arch>, e.g. Linux-64bit)
+}
+
+// NewProperties creates Properties for the given version with all defaults.
+func NewProperties(version string) Properties {
+	return Properties{
+		Version:     release.NormalizeVersion(version),
+		ReleasesUrl: release.DefaultBaseUrl,
+		Checksums:   map[string]string{},
+	}
+}
+
+// ReadProperties reads the Properties from the given file.
+func ReadProperties(file string) (Properties, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return Properties{}, fmt.Errorf("cannot read wrapper properties '%s': %v", file, err)
+	}
+	//noinspection GoUnhandledErrorResult
+	defer f.Close()
+
+	result := Properties{Checksums: map[string]string{}}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 {
+			return Properties{}, fmt.Errorf("cannot read wrapper properties '%s': illegal line: %s", file, line)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+		switch {
+		case key == "version":
+			result.Version = value
+		case key == "goVersion":
+			result.GoVersion = value
+		case key == "releasesUrl":
+			result.ReleasesUrl = value
+		case key == "cacheDir":
+			result.CacheDir = value
+		case strings.HasPrefix(key, checksumPropertyPrefix):
+			result.Checksums[key[len(checksumPropertyPrefix):]] = value
+		default:
+			return Properties{}, fmt.Errorf("cannot read wrapper properties '%s': unknown property: %s", file, key)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Properties{}, fmt.Errorf("cannot read wrapper properties '%s': %v", file, err)
+	}
+	return result, nil
+}
+
+// Bytes renders the properties in the format expected by the wrapper scripts.
+// Keys and values are separated by "=" without any spaces because mageplusw.cmd
+// is not able to handle them.
+func (instance Properties) Bytes() []byte {
+	buf := new(bytes.Buffer)
+	buf.WriteString("# Configuration of the mageplus wrapper (mageplusw, mageplusw.cmd and mageplusw.ps1).\n")
+	buf.WriteString("# Generated by: mageplus -wrapper\n")
+	buf.WriteString("\n")
+	buf.WriteString("# Version of mageplus to use.\n")
+	_, _ = fmt.Fprintf(buf, "version=%s\n", instance.Version)
+	buf.WriteString("\n")
+	buf.WriteString("# Version of the golang SDK mageplus should use\n")
+	buf.WriteString("# (default: $GO_VERSION or any available; set by: mageplus -wrapper -go <version>).\n")
+	if instance.GoVersion == "" {
+		buf.WriteString("#goVersion=\n")
+	} else {
+		_, _ = fmt.Fprintf(buf, "goVersion=%s\n", instance.GoVersion)
+	}
+	buf.WriteString("\n")
+	buf.WriteString("# Base URL where the releases of mageplus are downloaded from.\n")
+	_, _ = fmt.Fprintf(buf, "releasesUrl=%s\n", instance.ReleasesUrl)
+	buf.WriteString("\n")
+	buf.WriteString("# Directory where the downloaded binaries are cached\n")
+	buf.WriteString("# (default: ~/.mageplus/binaries or %LOCALAPPDATA%\\mageplus\\binaries).\n")
+	if instance.CacheDir == "" {
+		buf.WriteString("#cacheDir=\n")
+	} else {
+		_, _ = fmt.Fprintf(buf, "cacheDir=%s\n", instance.CacheDir)
+	}
+	if len(instance.Checksums) > 0 {
+		buf.WriteString("\n")
+		buf.WriteString("# SHA-256 of the release artifacts of the version above by platform.\n")
+		platforms := make([]string, 0, len(instance.Checksums))
+		for platform := range instance.Checksums {
+			platforms = append(platforms, platform)
+		}
+		sort.Strings(platforms)
+		for _, platform := range platforms {
+			_, _ = fmt.Fprintf(buf, "%s%s=%s\n", checksumPropertyPrefix, platform, instance.Checksums[platform])
+		}
+	}
+	return buf.Bytes()
+}
+
+// Write writes the properties to the given file.
+func (instance Properties) Write(file string) error {
+	if err := createDirectorsForFileIfRequired(file); err != nil {
+		return err
+	}
+	if err := mio.WriteFileAtomic(file, instance.Bytes(), 0644); err != nil {
+		return fmt.Errorf("cannot write wrapper properties '%s': %v", file, err)
+	}
+	return nil
+}
+
+// LoadProperties reads the Properties of the wrapper inside of targetDir. If
+// there is no wrapper the returned bool is false.
+func LoadProperties(targetDir string) (Properties, bool, error) {
+	file := filepath.Join(targetDir, PropertiesFile)
+	if ok, err := exists(file); err != nil {
+		return Properties{}, false, err
+	} else if !ok {
+		return Properties{}, false, nil
+	}
+	result, err := ReadProperties(file)
+	if err != nil {
+		return Properties{}, false, err
+	}
+	return result, true, nil
+}
+
+// propertiesFor returns the properties which should be written for the given
+// options into targetDir. Existing settings are preserved.
+func propertiesFor(targetDir string, options Options) (Properties, error) {
+	result, ok, err := LoadProperties(targetDir)
+	if err != nil {
+		return Properties{}, err
+	} else if !ok {
+		result = NewProperties(options.Version)
+	}
+	version := release.NormalizeVersion(options.Version)
+	if result.Version != version {
+		// The checksums belong to the old version.
+		result.Checksums = map[string]string{}
+	}
+	result.Version = version
+	if options.ReleasesUrl != "" && options.ReleasesUrl != result.ReleasesUrl {
+		result.ReleasesUrl = strings.TrimSuffix(options.ReleasesUrl, "/")
+	}
+	if options.GoVersion != "" {
+		result.GoVersion = options.GoVersion
+	}
+	if result.ReleasesUrl == "" {
+		result.ReleasesUrl = release.DefaultBaseUrl
+	}
+	return result, nil
+}
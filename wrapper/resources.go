@@ -1,6 +1,7 @@
 package wrapper
 
 func init() {
-	unixScript = `IyEvYmluL3NoCiMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIwojIyAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIyMKIyMgIG1hZ2VwbHVzIGJvb3RzdHJhcCB3cmFwcGVyIGZvciAqTklYIHN5c3RlbXMgICAgICAgICAgICAgICAgICAgICAgICAgICAgICMjCiMjICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAjIwojIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMKdmVyc2lvbj0iIyMjI1ZFUlNJT04jIyMjIgoKIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjCiMjICBETyBOT1QgRURJVCBCRUxPVyEhICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAjIwojIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMKZmF0YWwoKSB7CiAgICBlY2hvICJGQVRBTDogJCoiIDE-JjIKICAgIGV4aXQgMQp9CgppbmZvKCkgewogICAgZWNobyAiSU5GTzogJCoiIDE-JjIKfQoKZG9Eb3dubG9hZCgpIHsKICAgIGJpbmFyeURvd25sb2FkVXJsPSJodHRwczovL2dpdGh1Yi5jb20vZWNob2NhdC9tYWdlcGx1cy9yZWxlYXNlcy9kb3dubG9hZC92JHt2ZXJzaW9ufS9tYWdlcGx1c18ke3ZlcnNpb259XyR7b3N9LSR7YXJjaH0ke2Rvd25sb2FkRXh0fSIKICAgIHRtcERpcmVjdG9yeT0iJHtiaW5hcnl9LnRtcCIKICAgIGluZm8gIkRvd25sb2FkaW5nICR7YmluYXJ5RG93bmxvYWRVcmx9Li4uIgoKICAgIG1rZGlyIC1wICIke3RtcERpcmVjdG9yeX0iCiAgICBpZiBbICIkPyIgIT0gIjAiIF07IHRoZW4KICAgICAgICBmYXRhbCAiQ2Fubm90IGNyZWF0ZSBjYWNoZSBkaXJlY3RvcnkgZm9yIHN0b3JpbmcgYmluYXJpZXMuIFNlZSBhYm92ZS4iCiAgICBmaQoKICAgIGlmIGNvbW1hbmQgLXYgY3VybCA-IC9kZXYvbnVsbDsgdGhlbgogICAgICAgIGN1cmwgLXNTTGYgIiR7YmluYXJ5RG93bmxvYWRVcmx9IiA-ICIke3RtcERpcmVjdG9yeX0vdG1wLnRhci5neiIKICAgICAgICBpZiBbICIkPyIgIT0gIjAiIF07IHRoZW4KICAgICAgICAgICAgZmF0YWwgIldhcyBub3QgYWJsZSB0byBkb3dubG9hZCBiaW5hcnkgZnJvbSAke2JpbmFyeURvd25sb2FkVXJsfS4gU2VlIGFib3ZlLiIKICAgICAgICBmaQogICAgZWxpZiBjb21tYW5kIC12IHdnZXQgPiAvZGV2L251bGw7IHRoZW4KICAgICAgICB3Z2V0IC1xIC1PICIke3RtcERpcmVjdG9yeX0vdG1wLnRhci5neiIgIiR7YmluYXJ5RG93bmxvYWRVcmx9IgogICAgICAgIGlmIFsgIiQ_IiAhPSAiMCIgXTsgdGhlbgogICAgICAgICAgICBmYXRhbCAiV2FzIG5vdCBhYmxlIHRvIGRvd25sb2FkIGJpbmFyeSBmcm9tICR7YmluYXJ5RG93bmxvYWRVcmx9LiBTZWUgYWJvdmUuIgogICAgICAgIGZpCiAgICBlbHNlCiAgICAgICAgZmF0YWwgIk5laXRoZXIgY3VybCBub3Igd2dldCBmb3VuZCBpbiBcJFBBVEguIFBsZWFzZSBpbnN0YWxsIGF0IGxlYXN0IG9uZSBvZiB0aG9zZSB0b29scy4iCiAgICBmaQoKICAgIHRhciAteHpmICIke3RtcERpcmVjdG9yeX0vdG1wLnRhci5neiIgLUMgIiR7dG1wRGlyZWN0b3J5fSIKICAgIGlmIFsgIiQ_IiAhPSAiMCIgXTsgdGhlbgogICAgICAgIGZhdGFsICJXYXMgbm90IGFibGUgdG8gZXh0cmFjdCAke3RtcERpcmVjdG9yeX0vdG1wLnRhci5nei4gU2VlIGFib3ZlLiIKICAgIGZpCgogICAgY2htb2QgK3ggIiR7dG1wRGlyZWN0b3J5fS9tYWdlcGx1cyIKICAgIGlmIFsgIiQ_IiAhPSAiMCIgXTsgdGhlbgogICAgICAgIGZhdGFsICJXYXMgbm90IGFibGUgdG8gbWFrZSAke3RtcERpcmVjdG9yeX0vbWFnZXBsdXMgZXhlY3V0YWJsZS4gU2VlIGFib3ZlLiIKICAgIGZpCgogICAgbXYgIiR7dG1wRGlyZWN0b3J5fS9tYWdlcGx1cyIgIiR7YmluYXJ5fSIKICAgIGlmIFsgIiQ_IiAhPSAiMCIgXTsgdGhlbgogICAgICAgIGZhdGFsICJXYXMgbm90IGFibGUgdG8gbW92ZSAke3RtcERpcmVjdG9yeX0vbWFnZXBsdXMgdG8gJHtiaW5hcnl9LiBTZWUgYWJvdmUuIgogICAgZmkKCiAgICBybSAtcmYgIiR7dG1wRGlyZWN0b3J5fSIKICAgIGlmIFsgIiQ_IiAhPSAiMCIgXTsgdGhlbgogICAgICAgIGZhdGFsICJXYXMgbm90IGFibGUgdG8gY2xlYW4gdXAgJHt0bXBEaXJlY3Rvcnl9LiBTZWUgYWJvdmUuIgogICAgZmkKfQoKcGxhaW5Pcz0iJCh1bmFtZSAtcykiCmNhc2UgIiR7cGxhaW5Pc30iIGluCiAgICBMaW51eCopICAgICAgICBvcz0iTGludXgiOzsKICAgIERhcndpbiopICAgICAgIG9zPSJtYWNPUyI7OwogICAgRnJlZUJTRCopICAgICAgb3M9IkZyZWVCU0QiOzsKICAgIE9wZW5CU0QqKSAgICAgIG9zPSJPcGVuQlNEIjs7CiAgICBOZXRCU0QqKSAgICAgICBvcz0iTmV0QlNEIjs7CiAgICBEcmFnb25GbHlCU0QqKSBvcz0iRHJhZ29uRmx5QlNEIjs7CiAgICBDWUdXSU4qKSAgICAgICBvcz0iV2luZG93cyI7OwogICAgTUlOR1cqKSAgICAgICAgb3M9IldpbmRvd3MiOzsKICAgICopICAgICAgICAgICAgIGZhdGFsICJVbnN1cHBvcnRlZCBvcGVyYXRpbmcgc3lzdGVtOiAke3BsYWluT3N9Igplc2FjCgpwbGFpbkFyY2g9IiQodW5hbWUgLW0pIgpjYXNlICIke3BsYWluQXJjaH0iIGluCiAgICB4ODZfNjQqKSAgICAgICBhcmNoPSI2NGJpdCI7OwogICAgaTM4NiopICAgICAgICAgYXJjaD0iMzJiaXQiOzsKICAgIGFybTY0KikgICAgICAgIGFyY2g9IkFSTTY0Ijs7CiAgICBhcm0qKSAgICAgICAgICBhcmNoPSJBUk0iOzsKICAgICopICAgICAgICAgICAgIGZhdGFsICJVbnN1cHBvcnRlZCBhcmNoaXRlY3R1cmU6ICR7cGxhaW5BcmNofSIKZXNhYwoKY2FzZSAiJHtvc30iIGluCiAgICB3aW5kb3dzKikgICBleHQ9Ii5leGUiOzsKICAgICopICAgICAgICAgIGV4dD0iIjs7CmVzYWMKCmNhc2UgIiR7b3N9IiBpbgogICAgd2luZG93cyopICAgZG93bmxvYWRFeHQ9Ii56aXAiOzsKICAgICopICAgICAgICAgIGRvd25sb2FkRXh0PSIudGFyLmd6Ijs7CmVzYWMKCmJpbmFyaWVzQ2FjaGVEaXI9IiR7SE9NRX0vLm1hZ2VwbHVzL2JpbmFyaWVzIgpiaW5hcnlGaWxlTmFtZT0ibWFnZXBsdXMtJHtvc30tJHthcmNofS0ke3ZlcnNpb259JHtleHR9IgpiaW5hcnk9IiR7YmluYXJpZXNDYWNoZURpcn0vJHtiaW5hcnlGaWxlTmFtZX0iCgppZiBbICIke01BR0VQTFVTV19JR05PUkVfRE9DS0VSX0lNQUdFX01JU01BVENIfSIgIT0gInllcyIgXTsgdGhlbgogICAgaWYgWyAtciAiL3Vzci9saWIvbWFnZXBsdXMvZG9ja2VyLXZlcnNpb24iIF07IHRoZW4KICAgICAgICBkb2NrZXJWZXJzaW9uPSIkKGNhdCAvdXNyL2xpYi9tYWdlcGx1cy9kb2NrZXItdmVyc2lvbikiCiAgICAgICAgaWYgWyAiJHtkb2NrZXJWZXJzaW9ufSIgIT0gIiR7dmVyc2lvbn0iIF07IHRoZW4KICAgICAgICAgICAgaWYgWyAtciAiL3Vzci9saWIvbWFnZXBsdXMvZG9ja2VyLWltYWdlIiBdOyB0aGVuCiAgICAgICAgICAgICAgICBkb2NrZXJJbWFnZT0iJChjYXQgL3Vzci9saWIvbWFnZXBsdXMvZG9ja2VyLWltYWdlKSIKICAgICAgICAgICAgZWxzZQogICAgICAgICAgICAgICAgZG9ja2VySW1hZ2U9ImVjaG9jYXQvbWFnZXBsdXMiCiAgICAgICAgICAgIGZpCiAgICAgICAgICAgIGZhdGFsICJZb3UncmUgYXJlIHVzaW5nIG1hZ2VwbHVzdyB3aXRoIHZlcnNpb24gJHt2ZXJzaW9ufSBpbnNpZGUgb2YgYSBtYWdlcGx1cyBkb2NrZXIgaW1hZ2Ugd2l0aCB2ZXJzaW9uICR7ZG9ja2VyVmVyc2lvbn0uIiBcCiAgICAgICAgICAgICAgICAgICJUaGlzIGNvdWxkIGxlYWQgdG8gdW5leHBlY3RlZCBiZWhhdmlvcnMuIFdlIHJlY29tbWVuZCB0byBhbGlnbiBib3RoIHZlcnNpb25zIHRvZ2V0aGVyIGJ5IGVpdGhlcjoiIFwKICAgICAgICAgICAgICAgICAgIlxuXHQxLikgQ2hhbmdlICR7MH0gdG86IHZlcnNpb249XCIke2RvY2tlclZlcnNpb259XCIiIFwKICAgICAgICAgICAgICAgICAgIlxuXHQyLikgLi4uIG9yIHNldCB0aGUgdXNlZCBpbWFnZSB0bzogJHtkb2NrZXJJbWFnZX06JHt2ZXJzaW9ufSIgXAogICAgICAgICAgICAgICAgICAiXG5Zb3UgY2FuIHN1cHByZXNzIHRoaXMgZXJyb3IgYnkgc2V0IE1BR0VQTFVTV19JR05PUkVfRE9DS0VSX0lNQUdFX01JU01BVENIPXllcyIKICAgICAgICBmaQogICAgZmkKZmkKCmlmIFsgLXggIiR7YmluYXJ5fSIgXTsgdGhlbgogICAgIiR7YmluYXJ5fSIgLS12ZXJzaW9uIDI-JjEgfCBncmVwICIke3ZlcnNpb259IiA-IC9kZXYvbnVsbAogICAgaWYgWyAiJD8iICE9ICIwIiBdOyB0aGVuCiAgICAgICAgZG9Eb3dubG9hZAogICAgZmkKZWxzZQogICAgZG9Eb3dubG9hZApmaQoKIiR7YmluYXJ5fSIgIiRAIgo`
-	windowsScript = `QEVDSE8gT0ZGClNFVExPQ0FMClJFTSAjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMKUkVNICMjICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAjIwpSRU0gIyMgIG1hZ2VwbHVzIGJvb3RzdHJhcCB3cmFwcGVyIGZvciBXaW5kb3dzIHN5c3RlbXMgICAgICAgICAgICAgICAgICAgICAgICAgICMjClJFTSAjIyAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIyMKUkVNICMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIwpSRU0gIyMgIERPIE5PVCBFRElUISEgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICMjClJFTSAjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMKClNFVCBkaXJOYW1lPSV-ZHAwClNFVCBvcz1XaW5kb3dzClNFVCBhcmNoPTMyYml0ClNFVCBleHQ9LmV4ZQpTRVQgZG93bmxvYWRFeHQ9LnppcApJRiAiJVBST0NFU1NPUl9BUkNISVRFQ1RVUkUlIiA9PSAiQU1ENjQiICgKICAgIFNFVCBhcmNoPTY0Yml0CikKCklGIE5PVCBFWElTVCAiJWRpck5hbWUlXG1hZ2VwbHVzdyIgKAogICAgQ0FMTCA6ZmF0YWwgVGhpcyBtYWdlcGx1cyB3cmFwcGVyIHdhcyBub3QgaW5pdGlhdGVkIGNvcnJlY3RseS4gVHJ5IGRvd25sb2FkIG1hZ2VwbHVzIGJpbmFyeSBhbmQgcnVuOiBtYWdlcGx1cyAtd3JhcHBlcgogICAgRVhJVCAvYiAxCikKU0VUIGZpbmRDbWQ9RklORFNUUiAvQiAidmVyc2lvbj0iICIlZGlyTmFtZSVcbWFnZXBsdXN3IgpGT1IgL2YgJSVpIElOICgnJWZpbmRDbWQlJykgRE8gU0VUIHZlcnNpb25MaW5lPSUlaQpTRVQgdmVyc2lvbj0ldmVyc2lvbkxpbmU6fjksLTElCgpTRVQgYmluYXJpZXNDYWNoZURpcj0lTE9DQUxBUFBEQVRBJVxtYWdlcGx1c1xiaW5hcmllcwpJRiBOT1QgRVhJU1QgIiViaW5hcmllc0NhY2hlRGlyJSIgKAogICAgbWQgIiViaW5hcmllc0NhY2hlRGlyJSIKKQpJRiBOT1QgRVJST1JMRVZFTCAwICgKICAgIENBTEwgOmZhdGFsICJDYW5ub3QgY3JlYXRlIGNhY2hlIGRpcmVjdG9yeSBmb3Igc3RvcmluZyBiaW5hcmllcy4gU2VlIGFib3ZlLiIKKQpTRVQgYmluYXJ5RmlsZU5hbWU9bWFnZXBsdXMtJW9zJS0lYXJjaCUtJXZlcnNpb24lJWV4dCUKU0VUIGJpbmFyeT0lYmluYXJpZXNDYWNoZURpciVcJWJpbmFyeUZpbGVOYW1lJQoKSUYgTk9UIEVYSVNUICIlYmluYXJ5JSIgKAogICAgQ0FMTCA6ZG9Eb3dubG9hZAopIEVMU0UgKAogICAgIiViaW5hcnklIiB2ZXJzaW9uIDI-JjEgfCBmaW5kICIldmVyc2lvbiUiID4gTlVMCiAgICBJRiBOT1QgRVJST1JMRVZFTCAwICgKICAgICAgICBDQUxMIDpkb0Rvd25sb2FkCiAgICApCikKCklGICIlRVJST1JMRVZFTCUiID09ICIwIiAoCiAgICAiJWJpbmFyeSUiICUqCikKRVhJVCAvYiAlRVJST1JMRVZFTCUKR09UTyA6ZW9mU3VjY2VzcwoKOmRvRG93bmxvYWQKICAgIFNFVExPQ0FMCiAgICBTRVQgYmluYXJ5RG93bmxvYWRVcmw9aHR0cHM6Ly9naXRodWIuY29tL2VjaG9jYXQvbWFnZXBsdXMvcmVsZWFzZXMvZG93bmxvYWQvdiV2ZXJzaW9uJS9tYWdlcGx1c18ldmVyc2lvbiVfJW9zJS0lYXJjaCUlZG93bmxvYWRFeHQlCiAgICBDQUxMIDppbmZvIERvd25sb2FkaW5nICViaW5hcnlEb3dubG9hZFVybCUuLi4KCiAgICBTRVQgdG1wRGlyZWN0b3J5PSViaW5hcnklLiVSQU5ET00lLnRtcAogICAgUG93ZXJTaGVsbCAtQ29tbWFuZCAiTmV3LUl0ZW0gLVBhdGggJyV0bXBEaXJlY3RvcnklJyAtVHlwZSBEaXJlY3RvcnkgLUZvcmNlIHwgT3V0LU51bGw7IChOZXctT2JqZWN0IE5ldC5XZWJDbGllbnQpLkRvd25sb2FkRmlsZSgnJWJpbmFyeURvd25sb2FkVXJsJScsJyV0bXBEaXJlY3RvcnklXHRtcC56aXAnKTsgRXhwYW5kLUFyY2hpdmUgJyV0bXBEaXJlY3RvcnklXHRtcC56aXAnIC1EZXN0aW5hdGlvblBhdGggJyV0bXBEaXJlY3RvcnklJzsgTW92ZS1JdGVtICcldG1wRGlyZWN0b3J5JVxtYWdlcGx1cy5leGUnICclYmluYXJ5JSc7IFJlbW92ZS1JdGVtICcldG1wRGlyZWN0b3J5JScgLVJlY3Vyc2UgLUZvcmNlIgogICAgSUYgIiVFUlJPUkxFVkVMJSIgTkVRICIwIiAoCiAgICAgICAgQ0FMTCA6ZmF0YWwgV2FzIG5vdCBhYmxlIHRvIGRvd25sb2FkIGJpbmFyeSBmcm9tICViaW5hcnlEb3dubG9hZFVybCUuIFNlZSBhYm92ZS4KICAgICkKICAgIEVORExPQ0FMCiAgICBFWElUIC9iICVFUlJPUkxFVkVMJQoKOmZhdGFsCiAgICBFQ0hPLkZBVEFMOiAlKgogICAgR09UTyA6ZW9mRXJyb3IKICAgIEVYSVQgL2IgMQoKOmluZm8KICAgIEVDSE8uSU5GTzogJSoKICAgIEVYSVQgL2IgMAoKOmVvZkVycm9yCkVYSVQgL2IgMQpHT1RPIDplb2YKCjplb2ZTdWNjZXNzCkVYSVQgL2IgMAo`
+	unixScript = `IyEvYmluL3NoCiMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIwojIyAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIyMKIyMgIG1hZ2VwbHVzIGJvb3RzdHJhcCB3cmFwcGVyIGZvciAqTklYIHN5c3RlbXMgICAgICAgICAgICAgICAgICAgICAgICAgICAgICMjCiMjICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAjIwojIyAgVGhlIGNvbmZpZ3VyYXRpb24gaXMgbG9jYXRlZCBpbiAubWFnZXBsdXMvd3JhcHBlci5wcm9wZXJ0aWVzICAgICAgICAgICAgIyMKIyMgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICMjCiMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIwojIyAgRE8gTk9UIEVESVQhISAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIyMKIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjCmZhdGFsKCkgewogICAgZWNobyAiRkFUQUw6ICQqIiAxPiYyCiAgICBleGl0IDEKfQoKaW5mbygpIHsKICAgIGVjaG8gIklORk86ICQqIiAxPiYyCn0KCnByb3BlcnR5KCkgewogICAgc2VkIC1uICJzL15bWzpzcGFjZTpdXSokezF9W1s6c3BhY2U6XV0qPVtbOnNwYWNlOl1dKi8vcCIgIiR7cHJvcGVydGllc0ZpbGV9IiB8IHRhaWwgLW4gMSB8IHRyIC1kICdccicKfQoKZG93bmxvYWQoKSB7CiAgICBpZiBjb21tYW5kIC12IGN1cmwgPiAvZGV2L251bGw7IHRoZW4KICAgICAgICBpZiBbIC1uICIke01BR0VQTFVTV19UT0tFTn0iIF07IHRoZW4KICAgICAgICAgICAgY3VybCAtc1NMZiAtSCAiQXV0aG9yaXphdGlvbjogQmVhcmVyICR7TUFHRVBMVVNXX1RPS0VOfSIgIiR7MX0iID4gIiR7Mn0iCiAgICAgICAgZWxpZiBbIC1uICIke01BR0VQTFVTV19VU0VSTkFNRX0iIF07IHRoZW4KICAgICAgICAgICAgY3VybCAtc1NMZiAtdSAiJHtNQUdFUExVU1dfVVNFUk5BTUV9OiR7TUFHRVBMVVNXX1BBU1NXT1JEfSIgIiR7MX0iID4gIiR7Mn0iCiAgICAgICAgZWxzZQogICAgICAgICAgICBjdXJsIC1zU0xmICIkezF9IiA-ICIkezJ9IgogICAgICAgIGZpCiAgICBlbGlmIGNvbW1hbmQgLXYgd2dldCA-IC9kZXYvbnVsbDsgdGhlbgogICAgICAgIGlmIFsgLW4gIiR7TUFHRVBMVVNXX1RPS0VOfSIgXTsgdGhlbgogICAgICAgICAgICB3Z2V0IC1xIC0taGVhZGVyPSJBdXRob3JpemF0aW9uOiBCZWFyZXIgJHtNQUdFUExVU1dfVE9LRU59IiAtTyAiJHsyfSIgIiR7MX0iCiAgICAgICAgZWxpZiBbIC1uICIke01BR0VQTFVTV19VU0VSTkFNRX0iIF07IHRoZW4KICAgICAgICAgICAgd2dldCAtcSAtLWF1dGgtbm8tY2hhbGxlbmdlIC0tdXNlcj0iJHtNQUdFUExVU1dfVVNFUk5BTUV9IiAtLXBhc3N3b3JkPSIke01BR0VQTFVTV19QQVNTV09SRH0iIC1PICIkezJ9IiAiJHsxfSIKICAgICAgICBlbHNlCiAgICAgICAgICAgIHdnZXQgLXEgLU8gIiR7Mn0iICIkezF9IgogICAgICAgIGZpCiAgICBlbHNlCiAgICAgICAgZmF0YWwgIk5laXRoZXIgY3VybCBub3Igd2dldCBmb3VuZCBpbiBcJFBBVEguIFBsZWFzZSBpbnN0YWxsIGF0IGxlYXN0IG9uZSBvZiB0aG9zZSB0b29scy4iCiAgICBmaQp9Cgp2ZXJpZnlDaGVja3N1bSgpIHsKICAgIGV4cGVjdGVkQ2hlY2tzdW09IiQocHJvcGVydHkgImNoZWNrc3VtLiR7b3N9LSR7YXJjaH0iKSIKICAgIGlmIFsgLXogIiR7ZXhwZWN0ZWRDaGVja3N1bX0iIF07IHRoZW4KICAgICAgICBmYXRhbCAiVGhlcmUgaXMgbm8gY2hlY2tzdW0gZm9yICR7b3N9LSR7YXJjaH0gY29uZmlndXJlZCBpbiAke3Byb3BlcnRpZXNGaWxlfS4gVHJ5IGRvd25sb2FkIG1hZ2VwbHVzIGJpbmFyeSBhbmQgcnVuOiBtYWdlcGx1cyAtd3JhcHBlciIKICAgIGZpCgogICAgaWYgY29tbWFuZCAtdiBzaGEyNTZzdW0gPiAvZGV2L251bGw7IHRoZW4KICAgICAgICBhY3R1YWxDaGVja3N1bT0iJChzaGEyNTZzdW0gIiR7MX0iKSIgfHwgZmF0YWwgIldhcyBub3QgYWJsZSB0byBjYWxjdWxhdGUgY2hlY2tzdW0gb2YgJHsxfS4gU2VlIGFib3ZlLiIKICAgIGVsaWYgY29tbWFuZCAtdiBzaGFzdW0gPiAvZGV2L251bGw7IHRoZW4KICAgICAgICBhY3R1YWxDaGVja3N1bT0iJChzaGFzdW0gLWEgMjU2ICIkezF9IikiIHx8IGZhdGFsICJXYXMgbm90IGFibGUgdG8gY2FsY3VsYXRlIGNoZWNrc3VtIG9mICR7MX0uIFNlZSBhYm92ZS4iCiAgICBlbHNlCiAgICAgICAgZmF0YWwgIk5laXRoZXIgc2hhMjU2c3VtIG5vciBzaGFzdW0gZm91bmQgaW4gXCRQQVRILiBQbGVhc2UgaW5zdGFsbCBhdCBsZWFzdCBvbmUgb2YgdGhvc2UgdG9vbHMuIgogICAgZmkKICAgIGFjdHVhbENoZWNrc3VtPSIkKGVjaG8gIiR7YWN0dWFsQ2hlY2tzdW19IiB8IGN1dCAtZCAnICcgLWYgMSkiCgogICAgaWYgWyAiJHthY3R1YWxDaGVja3N1bX0iICE9ICIke2V4cGVjdGVkQ2hlY2tzdW19IiBdOyB0aGVuCiAgICAgICAgcm0gLXJmICIke3RtcERpcmVjdG9yeX0iCiAgICAgICAgZmF0YWwgIkNoZWNrc3VtIG1pc21hdGNoIG9mICR7YmluYXJ5RG93bmxvYWRVcmx9OiBleHBlY3RlZCAke2V4cGVjdGVkQ2hlY2tzdW19IGJ1dCBnb3QgJHthY3R1YWxDaGVja3N1bX0uIgogICAgZmkKfQoKZG9Eb3dubG9hZCgpIHsKICAgIGJpbmFyeURvd25sb2FkVXJsPSIke3JlbGVhc2VzVXJsfS9kb3dubG9hZC92JHt2ZXJzaW9ufS9tYWdlcGx1c18ke3ZlcnNpb259XyR7b3N9LSR7YXJjaH0ke2Rvd25sb2FkRXh0fSIKICAgIGlmIFsgLW4gIiR7TUFHRVBMVVNXX0FSQ0hJVkV9IiBdOyB0aGVuCiAgICAgICAgYmluYXJ5RG93bmxvYWRVcmw9IiR7TUFHRVBMVVNXX0FSQ0hJVkV9IgogICAgICAgIGluZm8gIkluc3RhbGxpbmcgJHtNQUdFUExVU1dfQVJDSElWRX0uLi4iCiAgICBlbHNlCiAgICAgICAgaW5mbyAiRG93bmxvYWRpbmcgJHtiaW5hcnlEb3dubG9hZFVybH0uLi4iCiAgICBmaQoKICAgIG1rZGlyIC1wICIke2JpbmFyaWVzQ2FjaGVEaXJ9IgogICAgaWYgWyAiJD8iICE9ICIwIiBdOyB0aGVuCiAgICAgICAgZmF0YWwgIkNhbm5vdCBjcmVhdGUgY2FjaGUgZGlyZWN0b3J5IGZvciBzdG9yaW5nIGJpbmFyaWVzLiBTZWUgYWJvdmUuIgogICAgZmkKICAgICMgVW5pcXVlIHBlciBwcm9jZXNzLCBzbyBjb25jdXJyZW50IGRvd25sb2FkcyBkbyBub3QgaW50ZXJmZXJlOyB0aGUgYmluYXJ5CiAgICAjIGlzIHJlbmFtZWQgaW50byBwbGFjZSBhdCB0aGUgZW5kLCB3aGljaCBpcyBhdG9taWMgaW5zaWRlIG9mIG9uZSBkaXJlY3RvcnkuCiAgICB0bXBEaXJlY3Rvcnk9IiQobWt0ZW1wIC1kICIke2JpbmFyaWVzQ2FjaGVEaXJ9Ly4ke2JpbmFyeUZpbGVOYW1lfS5YWFhYWFgiKSIKICAgIGlmIFsgIiQ_IiAhPSAiMCIgXTsgdGhlbgogICAgICAgIGZhdGFsICJDYW5ub3QgY3JlYXRlIHRlbXBvcmFyeSBkaXJlY3RvcnkgaW5zaWRlIG9mICR7YmluYXJpZXNDYWNoZURpcn0uIFNlZSBhYm92ZS4iCiAgICBmaQogICAgdHJhcCAncm0gLXJmICIke3RtcERpcmVjdG9yeX0iJyBFWElUCgogICAgaWYgWyAtbiAiJHtNQUdFUExVU1dfQVJDSElWRX0iIF07IHRoZW4KICAgICAgICBjcCAiJHtNQUdFUExVU1dfQVJDSElWRX0iICIke3RtcERpcmVjdG9yeX0vdG1wLnRhci5neiIKICAgICAgICBpZiBbICIkPyIgIT0gIjAiIF07IHRoZW4KICAgICAgICAgICAgZmF0YWwgIldhcyBub3QgYWJsZSB0byBjb3B5ICR7TUFHRVBMVVNXX0FSQ0hJVkV9LiBTZWUgYWJvdmUuIgogICAgICAgIGZpCiAgICBlbHNlCiAgICAgICAgZG93bmxvYWQgIiR7YmluYXJ5RG93bmxvYWRVcmx9IiAiJHt0bXBEaXJlY3Rvcnl9L3RtcC50YXIuZ3oiCiAgICAgICAgaWYgWyAiJD8iICE9ICIwIiBdOyB0aGVuCiAgICAgICAgICAgIGZhdGFsICJXYXMgbm90IGFibGUgdG8gZG93bmxvYWQgYmluYXJ5IGZyb20gJHtiaW5hcnlEb3dubG9hZFVybH0uIFNlZSBhYm92ZS4iCiAgICAgICAgZmkKICAgIGZpCgogICAgdmVyaWZ5Q2hlY2tzdW0gIiR7dG1wRGlyZWN0b3J5fS90bXAudGFyLmd6IgoKICAgIHRhciAteHpmICIke3RtcERpcmVjdG9yeX0vdG1wLnRhci5neiIgLUMgIiR7dG1wRGlyZWN0b3J5fSIKICAgIGlmIFsgIiQ_IiAhPSAiMCIgXTsgdGhlbgogICAgICAgIGZhdGFsICJXYXMgbm90IGFibGUgdG8gZXh0cmFjdCAke3RtcERpcmVjdG9yeX0vdG1wLnRhci5nei4gU2VlIGFib3ZlLiIKICAgIGZpCgogICAgY2htb2QgK3ggIiR7dG1wRGlyZWN0b3J5fS9tYWdlcGx1cyIKICAgIGlmIFsgIiQ_IiAhPSAiMCIgXTsgdGhlbgogICAgICAgIGZhdGFsICJXYXMgbm90IGFibGUgdG8gbWFrZSAke3RtcERpcmVjdG9yeX0vbWFnZXBsdXMgZXhlY3V0YWJsZS4gU2VlIGFib3ZlLiIKICAgIGZpCgogICAgbXYgIiR7dG1wRGlyZWN0b3J5fS9tYWdlcGx1cyIgIiR7YmluYXJ5fSIKICAgIGlmIFsgIiQ_IiAhPSAiMCIgXTsgdGhlbgogICAgICAgIGZhdGFsICJXYXMgbm90IGFibGUgdG8gbW92ZSAke3RtcERpcmVjdG9yeX0vbWFnZXBsdXMgdG8gJHtiaW5hcnl9LiBTZWUgYWJvdmUuIgogICAgZmkKCiAgICBybSAtcmYgIiR7dG1wRGlyZWN0b3J5fSIKICAgIGlmIFsgIiQ_IiAhPSAiMCIgXTsgdGhlbgogICAgICAgIGZhdGFsICJXYXMgbm90IGFibGUgdG8gY2xlYW4gdXAgJHt0bXBEaXJlY3Rvcnl9LiBTZWUgYWJvdmUuIgogICAgZmkKICAgIHRyYXAgLSBFWElUCn0KCnByb3BlcnRpZXNGaWxlPSIkKGRpcm5hbWUgIiR7MH0iKS8ubWFnZXBsdXMvd3JhcHBlci5wcm9wZXJ0aWVzIgppZiBbICEgLXIgIiR7cHJvcGVydGllc0ZpbGV9IiBdOyB0aGVuCiAgICBmYXRhbCAiVGhpcyBtYWdlcGx1cyB3cmFwcGVyIHdhcyBub3QgaW5pdGlhdGVkIGNvcnJlY3RseTogJHtwcm9wZXJ0aWVzRmlsZX0gaXMgbWlzc2luZy4gVHJ5IGRvd25sb2FkIG1hZ2VwbHVzIGJpbmFyeSBhbmQgcnVuOiBtYWdlcGx1cyAtd3JhcHBlciIKZmkKdmVyc2lvbj0iJChwcm9wZXJ0eSB2ZXJzaW9uKSIKaWYgWyAteiAiJHt2ZXJzaW9ufSIgXTsgdGhlbgogICAgZmF0YWwgIlRoZXJlIGlzIG5vIHZlcnNpb24gY29uZmlndXJlZCBpbiAke3Byb3BlcnRpZXNGaWxlfS4iCmZpCmdvVmVyc2lvbj0iJChwcm9wZXJ0eSBnb1ZlcnNpb24pIgpyZWxlYXNlc1VybD0iJChwcm9wZXJ0eSByZWxlYXNlc1VybCkiCmlmIFsgLW4gIiR7TUFHRVBMVVNXX0JBU0VfVVJMfSIgXTsgdGhlbgogICAgcmVsZWFzZXNVcmw9IiR7TUFHRVBMVVNXX0JBU0VfVVJMfSIKZWxpZiBbIC16ICIke3JlbGVhc2VzVXJsfSIgXTsgdGhlbgogICAgcmVsZWFzZXNVcmw9Imh0dHBzOi8vZ2l0aHViLmNvbS9lY2hvY2F0L21hZ2VwbHVzL3JlbGVhc2VzIgpmaQpiaW5hcmllc0NhY2hlRGlyPSIkKHByb3BlcnR5IGNhY2hlRGlyKSIKaWYgWyAteiAiJHtiaW5hcmllc0NhY2hlRGlyfSIgXTsgdGhlbgogICAgYmluYXJpZXNDYWNoZURpcj0iJHtIT01FfS8ubWFnZXBsdXMvYmluYXJpZXMiCmZpCgpwbGFpbk9zPSIkKHVuYW1lIC1zKSIKY2FzZSAiJHtwbGFpbk9zfSIgaW4KICAgIExpbnV4KikgICAgICAgIG9zPSJMaW51eCI7OwogICAgRGFyd2luKikgICAgICAgb3M9Im1hY09TIjs7CiAgICBGcmVlQlNEKikgICAgICBvcz0iRnJlZUJTRCI7OwogICAgT3BlbkJTRCopICAgICAgb3M9Ik9wZW5CU0QiOzsKICAgIE5ldEJTRCopICAgICAgIG9zPSJOZXRCU0QiOzsKICAgIERyYWdvbkZseUJTRCopIG9zPSJEcmFnb25GbHlCU0QiOzsKICAgIENZR1dJTiopICAgICAgIG9zPSJXaW5kb3dzIjs7CiAgICBNSU5HVyopICAgICAgICBvcz0iV2luZG93cyI7OwogICAgKikgICAgICAgICAgICAgZmF0YWwgIlVuc3VwcG9ydGVkIG9wZXJhdGluZyBzeXN0ZW06ICR7cGxhaW5Pc30iCmVzYWMKCnBsYWluQXJjaD0iJCh1bmFtZSAtbSkiCmNhc2UgIiR7cGxhaW5BcmNofSIgaW4KICAgIHg4Nl82NCopICAgICAgIGFyY2g9IjY0Yml0Ijs7CiAgICBpMzg2KikgICAgICAgICBhcmNoPSIzMmJpdCI7OwogICAgYXJtNjQqKSAgICAgICAgYXJjaD0iQVJNNjQiOzsKICAgIGFybSopICAgICAgICAgIGFyY2g9IkFSTSI7OwogICAgKikgICAgICAgICAgICAgZmF0YWwgIlVuc3VwcG9ydGVkIGFyY2hpdGVjdHVyZTogJHtwbGFpbkFyY2h9Igplc2FjCgpjYXNlICIke29zfSIgaW4KICAgIHdpbmRvd3MqKSAgIGV4dD0iLmV4ZSI7OwogICAgKikgICAgICAgICAgZXh0PSIiOzsKZXNhYwoKY2FzZSAiJHtvc30iIGluCiAgICB3aW5kb3dzKikgICBkb3dubG9hZEV4dD0iLnppcCI7OwogICAgKikgICAgICAgICAgZG93bmxvYWRFeHQ9Ii50YXIuZ3oiOzsKZXNhYwoKYmluYXJ5RmlsZU5hbWU9Im1hZ2VwbHVzLSR7b3N9LSR7YXJjaH0tJHt2ZXJzaW9ufSR7ZXh0fSIKYmluYXJ5PSIke2JpbmFyaWVzQ2FjaGVEaXJ9LyR7YmluYXJ5RmlsZU5hbWV9IgoKaWYgWyAiJHtNQUdFUExVU1dfSUdOT1JFX0RPQ0tFUl9JTUFHRV9NSVNNQVRDSH0iICE9ICJ5ZXMiIF07IHRoZW4KICAgIGlmIFsgLXIgIi91c3IvbGliL21hZ2VwbHVzL2RvY2tlci12ZXJzaW9uIiBdOyB0aGVuCiAgICAgICAgZG9ja2VyVmVyc2lvbj0iJChjYXQgL3Vzci9saWIvbWFnZXBsdXMvZG9ja2VyLXZlcnNpb24pIgogICAgICAgIGlmIFsgIiR7ZG9ja2VyVmVyc2lvbn0iICE9ICIke3ZlcnNpb259IiBdOyB0aGVuCiAgICAgICAgICAgIGlmIFsgLXIgIi91c3IvbGliL21hZ2VwbHVzL2RvY2tlci1pbWFnZSIgXTsgdGhlbgogICAgICAgICAgICAgICAgZG9ja2VySW1hZ2U9IiQoY2F0IC91c3IvbGliL21hZ2VwbHVzL2RvY2tlci1pbWFnZSkiCiAgICAgICAgICAgIGVsc2UKICAgICAgICAgICAgICAgIGRvY2tlckltYWdlPSJlY2hvY2F0L21hZ2VwbHVzIgogICAgICAgICAgICBmaQogICAgICAgICAgICBmYXRhbCAiWW91J3JlIGFyZSB1c2luZyBtYWdlcGx1c3cgd2l0aCB2ZXJzaW9uICR7dmVyc2lvbn0gaW5zaWRlIG9mIGEgbWFnZXBsdXMgZG9ja2VyIGltYWdlIHdpdGggdmVyc2lvbiAke2RvY2tlclZlcnNpb259LiIgXAogICAgICAgICAgICAgICAgICAiVGhpcyBjb3VsZCBsZWFkIHRvIHVuZXhwZWN0ZWQgYmVoYXZpb3JzLiBXZSByZWNvbW1lbmQgdG8gYWxpZ24gYm90aCB2ZXJzaW9ucyB0b2dldGhlciBieSBlaXRoZXI6IiBcCiAgICAgICAgICAgICAgICAgICJcblx0MS4pIENoYW5nZSAke3Byb3BlcnRpZXNGaWxlfSB0bzogdmVyc2lvbj0ke2RvY2tlclZlcnNpb259IiBcCiAgICAgICAgICAgICAgICAgICJcblx0Mi4pIC4uLiBvciBzZXQgdGhlIHVzZWQgaW1hZ2UgdG86ICR7ZG9ja2VySW1hZ2V9OiR7dmVyc2lvbn0iIFwKICAgICAgICAgICAgICAgICAgIlxuWW91IGNhbiBzdXBwcmVzcyB0aGlzIGVycm9yIGJ5IHNldCBNQUdFUExVU1dfSUdOT1JFX0RPQ0tFUl9JTUFHRV9NSVNNQVRDSD15ZXMiCiAgICAgICAgZmkKICAgIGZpCmZpCgppZiBbIC14ICIke2JpbmFyeX0iIF07IHRoZW4KICAgICIke2JpbmFyeX0iIC0tdmVyc2lvbiAyPiYxIHwgZ3JlcCAiJHt2ZXJzaW9ufSIgPiAvZGV2L251bGwKICAgIGlmIFsgIiQ_IiAhPSAiMCIgXTsgdGhlbgogICAgICAgIGRvRG93bmxvYWQKICAgIGZpCmVsc2UKICAgIGRvRG93bmxvYWQKZmkKCmlmIFsgLW4gIiR7Z29WZXJzaW9ufSIgXTsgdGhlbgogICAgIiR7YmluYXJ5fSIgLWdvICIke2dvVmVyc2lvbn0iICIkQCIKZWxzZQogICAgIiR7YmluYXJ5fSIgIiRAIgpmaQo`
+	windowsScript = `QEVDSE8gT0ZGClNFVExPQ0FMClJFTSAjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMKUkVNICMjICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAjIwpSRU0gIyMgIG1hZ2VwbHVzIGJvb3RzdHJhcCB3cmFwcGVyIGZvciBXaW5kb3dzIHN5c3RlbXMgICAgICAgICAgICAgICAgICAgICAgICAgICMjClJFTSAjIyAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIyMKUkVNICMjICBUaGUgY29uZmlndXJhdGlvbiBpcyBsb2NhdGVkIGluIC5tYWdlcGx1c1x3cmFwcGVyLnByb3BlcnRpZXMgICAgICAgICAgICAjIwpSRU0gIyMgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICMjClJFTSAjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMKUkVNICMjICBETyBOT1QgRURJVCEhICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAjIwpSRU0gIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjCgpTRVQgZGlyTmFtZT0lfmRwMApTRVQgb3M9V2luZG93cwpTRVQgYXJjaD0zMmJpdApTRVQgZXh0PS5leGUKU0VUIGRvd25sb2FkRXh0PS56aXAKSUYgIiVQUk9DRVNTT1JfQVJDSElURUNUVVJFJSIgPT0gIkFNRDY0IiAoCiAgICBTRVQgYXJjaD02NGJpdAopCgpTRVQgcHJvcGVydGllc0ZpbGU9JWRpck5hbWUlLm1hZ2VwbHVzXHdyYXBwZXIucHJvcGVydGllcwpJRiBOT1QgRVhJU1QgIiVwcm9wZXJ0aWVzRmlsZSUiICgKICAgIENBTEwgOmZhdGFsIFRoaXMgbWFnZXBsdXMgd3JhcHBlciB3YXMgbm90IGluaXRpYXRlZCBjb3JyZWN0bHk6ICVwcm9wZXJ0aWVzRmlsZSUgaXMgbWlzc2luZy4gVHJ5IGRvd25sb2FkIG1hZ2VwbHVzIGJpbmFyeSBhbmQgcnVuOiBtYWdlcGx1cyAtd3JhcHBlcgogICAgRVhJVCAvYiAxCikKRk9SIC9GICJ1c2ViYWNrcSBlb2w9IyB0b2tlbnM9MSwqIGRlbGltcz09IiAlJWEgSU4gKCIlcHJvcGVydGllc0ZpbGUlIikgRE8gU0VUICJwcm9wZXJ0eS4lJWE9JSViIgpTRVQgdmVyc2lvbj0lcHJvcGVydHkudmVyc2lvbiUKSUYgIiV2ZXJzaW9uJSIgPT0gIiIgKAogICAgQ0FMTCA6ZmF0YWwgVGhlcmUgaXMgbm8gdmVyc2lvbiBjb25maWd1cmVkIGluICVwcm9wZXJ0aWVzRmlsZSUuCiAgICBFWElUIC9iIDEKKQpTRVQgZ29WZXJzaW9uPSVwcm9wZXJ0eS5nb1ZlcnNpb24lClNFVCByZWxlYXNlc1VybD0lcHJvcGVydHkucmVsZWFzZXNVcmwlCklGIERFRklORUQgTUFHRVBMVVNXX0JBU0VfVVJMICgKICAgIFNFVCByZWxlYXNlc1VybD0lTUFHRVBMVVNXX0JBU0VfVVJMJQopCklGICIlcmVsZWFzZXNVcmwlIiA9PSAiIiAoCiAgICBTRVQgcmVsZWFzZXNVcmw9aHR0cHM6Ly9naXRodWIuY29tL2VjaG9jYXQvbWFnZXBsdXMvcmVsZWFzZXMKKQpTRVQgYmluYXJpZXNDYWNoZURpcj0lcHJvcGVydHkuY2FjaGVEaXIlCklGICIlYmluYXJpZXNDYWNoZURpciUiID09ICIiICgKICAgIFNFVCBiaW5hcmllc0NhY2hlRGlyPSVMT0NBTEFQUERBVEElXG1hZ2VwbHVzXGJpbmFyaWVzCikKSUYgTk9UIEVYSVNUICIlYmluYXJpZXNDYWNoZURpciUiICgKICAgIG1kICIlYmluYXJpZXNDYWNoZURpciUiCikKSUYgTk9UIEVSUk9STEVWRUwgMCAoCiAgICBDQUxMIDpmYXRhbCAiQ2Fubm90IGNyZWF0ZSBjYWNoZSBkaXJlY3RvcnkgZm9yIHN0b3JpbmcgYmluYXJpZXMuIFNlZSBhYm92ZS4iCikKU0VUIGJpbmFyeUZpbGVOYW1lPW1hZ2VwbHVzLSVvcyUtJWFyY2glLSV2ZXJzaW9uJSVleHQlClNFVCBiaW5hcnk9JWJpbmFyaWVzQ2FjaGVEaXIlXCViaW5hcnlGaWxlTmFtZSUKCklGIE5PVCBFWElTVCAiJWJpbmFyeSUiICgKICAgIENBTEwgOmRvRG93bmxvYWQKKSBFTFNFICgKICAgICIlYmluYXJ5JSIgdmVyc2lvbiAyPiYxIHwgZmluZCAiJXZlcnNpb24lIiA-IE5VTAogICAgSUYgTk9UIEVSUk9STEVWRUwgMCAoCiAgICAgICAgQ0FMTCA6ZG9Eb3dubG9hZAogICAgKQopCgpJRiAiJUVSUk9STEVWRUwlIiA9PSAiMCIgKAogICAgSUYgIiVnb1ZlcnNpb24lIiA9PSAiIiAoCiAgICAgICAgIiViaW5hcnklIiAlKgogICAgKSBFTFNFICgKICAgICAgICAiJWJpbmFyeSUiIC1nbyAiJWdvVmVyc2lvbiUiICUqCiAgICApCikKRVhJVCAvYiAlRVJST1JMRVZFTCUKR09UTyA6ZW9mU3VjY2VzcwoKOmRvRG93bmxvYWQKICAgIFNFVExPQ0FMCiAgICBTRVQgYmluYXJ5RG93bmxvYWRVcmw9JXJlbGVhc2VzVXJsJS9kb3dubG9hZC92JXZlcnNpb24lL21hZ2VwbHVzXyV2ZXJzaW9uJV8lb3MlLSVhcmNoJSVkb3dubG9hZEV4dCUKICAgIElGIERFRklORUQgTUFHRVBMVVNXX0FSQ0hJVkUgKAogICAgICAgIFNFVCBiaW5hcnlEb3dubG9hZFVybD0lTUFHRVBMVVNXX0FSQ0hJVkUlCiAgICAgICAgQ0FMTCA6aW5mbyBJbnN0YWxsaW5nICVNQUdFUExVU1dfQVJDSElWRSUuLi4KICAgICkgRUxTRSAoCiAgICAgICAgQ0FMTCA6aW5mbyBEb3dubG9hZGluZyAlYmluYXJ5RG93bmxvYWRVcmwlLi4uCiAgICApCgogICAgQ0FMTCBTRVQgZXhwZWN0ZWRDaGVja3N1bT0lJXByb3BlcnR5LmNoZWNrc3VtLiVvcyUtJWFyY2glJSUKICAgIElGICIlZXhwZWN0ZWRDaGVja3N1bSUiID09ICIiICgKICAgICAgICBDQUxMIDpmYXRhbCBUaGVyZSBpcyBubyBjaGVja3N1bSBmb3IgJW9zJS0lYXJjaCUgY29uZmlndXJlZCBpbiAlcHJvcGVydGllc0ZpbGUlLiBUcnkgZG93bmxvYWQgbWFnZXBsdXMgYmluYXJ5IGFuZCBydW46IG1hZ2VwbHVzIC13cmFwcGVyCiAgICAgICAgRVhJVCAvYiAxCiAgICApCgogICAgU0VUIHRtcERpcmVjdG9yeT0lYmluYXJ5JS4lUkFORE9NJS50bXAKICAgIElGIERFRklORUQgTUFHRVBMVVNXX0FSQ0hJVkUgKAogICAgICAgIFBvd2VyU2hlbGwgLUNvbW1hbmQgIk5ldy1JdGVtIC1QYXRoICcldG1wRGlyZWN0b3J5JScgLVR5cGUgRGlyZWN0b3J5IC1Gb3JjZSB8IE91dC1OdWxsOyBDb3B5LUl0ZW0gJyVNQUdFUExVU1dfQVJDSElWRSUnICcldG1wRGlyZWN0b3J5JVx0bXAuemlwJyIKICAgICkgRUxTRSAoCiAgICAgICAgUG93ZXJTaGVsbCAtQ29tbWFuZCAiTmV3LUl0ZW0gLVBhdGggJyV0bXBEaXJlY3RvcnklJyAtVHlwZSBEaXJlY3RvcnkgLUZvcmNlIHwgT3V0LU51bGw7ICRjbGllbnQgPSBOZXctT2JqZWN0IE5ldC5XZWJDbGllbnQ7IGlmICgkZW52Ok1BR0VQTFVTV19UT0tFTikgeyAkY2xpZW50LkhlYWRlcnMuQWRkKCdBdXRob3JpemF0aW9uJywgJ0JlYXJlciAnICsgJGVudjpNQUdFUExVU1dfVE9LRU4pIH0gZWxzZWlmICgkZW52Ok1BR0VQTFVTV19VU0VSTkFNRSkgeyAkY2xpZW50LkhlYWRlcnMuQWRkKCdBdXRob3JpemF0aW9uJywgJ0Jhc2ljICcgKyBbQ29udmVydF06OlRvQmFzZTY0U3RyaW5nKFtUZXh0LkVuY29kaW5nXTo6VVRGOC5HZXRCeXRlcygkZW52Ok1BR0VQTFVTV19VU0VSTkFNRSArICc6JyArICRlbnY6TUFHRVBMVVNXX1BBU1NXT1JEKSkpIH07ICRjbGllbnQuRG93bmxvYWRGaWxlKCclYmluYXJ5RG93bmxvYWRVcmwlJywnJXRtcERpcmVjdG9yeSVcdG1wLnppcCcpIgogICAgKQogICAgSUYgIiVFUlJPUkxFVkVMJSIgTkVRICIwIiAoCiAgICAgICAgQ0FMTCA6ZmF0YWwgV2FzIG5vdCBhYmxlIHRvIGRvd25sb2FkIGJpbmFyeSBmcm9tICViaW5hcnlEb3dubG9hZFVybCUuIFNlZSBhYm92ZS4KICAgICAgICBFWElUIC9iIDEKICAgICkKCiAgICBSRU0gT3V0cHV0IG9mIGNlcnR1dGlsIHdoaWNoIGRvZXMgbm90IGNvbnRhaW4gYSBjaGVja3N1bSAoaW5jbHVkaW5nIHRoZQogICAgUkVNIG9uZSBvZiBmYWlsdXJlcykgYWx3YXlzIGNvbnRhaW5zIGEgY29sb24uCiAgICBTRVQgYWN0dWFsQ2hlY2tzdW09CiAgICBGT1IgL0YgImRlbGltcz0iICUlaCBJTiAoJ2NlcnR1dGlsIC1oYXNoZmlsZSAiJXRtcERpcmVjdG9yeSVcdG1wLnppcCIgU0hBMjU2IF58IGZpbmRzdHIgL3YgIjoiJykgRE8gU0VUICJhY3R1YWxDaGVja3N1bT0lJWgiCiAgICBJRiBOT1QgREVGSU5FRCBhY3R1YWxDaGVja3N1bSAoCiAgICAgICAgUk1ESVIgL1MgL1EgIiV0bXBEaXJlY3RvcnklIgogICAgICAgIENBTEwgOmZhdGFsIFdhcyBub3QgYWJsZSB0byBjYWxjdWxhdGUgY2hlY2tzdW0gb2YgJXRtcERpcmVjdG9yeSVcdG1wLnppcC4KICAgICAgICBFWElUIC9iIDEKICAgICkKICAgIFNFVCAiYWN0dWFsQ2hlY2tzdW09JWFjdHVhbENoZWNrc3VtOiA9JSIKICAgIElGIC9JIE5PVCAiJWFjdHVhbENoZWNrc3VtJSIgPT0gIiVleHBlY3RlZENoZWNrc3VtJSIgKAogICAgICAgIFJNRElSIC9TIC9RICIldG1wRGlyZWN0b3J5JSIKICAgICAgICBDQUxMIDpmYXRhbCBDaGVja3N1bSBtaXNtYXRjaCBvZiAlYmluYXJ5RG93bmxvYWRVcmwlOiBleHBlY3RlZCAlZXhwZWN0ZWRDaGVja3N1bSUgYnV0IGdvdCAlYWN0dWFsQ2hlY2tzdW0lLgogICAgICAgIEVYSVQgL2IgMQogICAgKQoKICAgIFBvd2VyU2hlbGwgLUNvbW1hbmQgIkV4cGFuZC1BcmNoaXZlICcldG1wRGlyZWN0b3J5JVx0bXAuemlwJyAtRGVzdGluYXRpb25QYXRoICcldG1wRGlyZWN0b3J5JSc7IE1vdmUtSXRlbSAnJXRtcERpcmVjdG9yeSVcbWFnZXBsdXMuZXhlJyAnJWJpbmFyeSUnOyBSZW1vdmUtSXRlbSAnJXRtcERpcmVjdG9yeSUnIC1SZWN1cnNlIC1Gb3JjZSIKICAgIElGICIlRVJST1JMRVZFTCUiIE5FUSAiMCIgKAogICAgICAgIENBTEwgOmZhdGFsIFdhcyBub3QgYWJsZSB0byBleHRyYWN0IGJpbmFyeSBmcm9tICViaW5hcnlEb3dubG9hZFVybCUuIFNlZSBhYm92ZS4KICAgICkKICAgIEVORExPQ0FMCiAgICBFWElUIC9iICVFUlJPUkxFVkVMJQoKOmZhdGFsCiAgICBFQ0hPLkZBVEFMOiAlKgogICAgR09UTyA6ZW9mRXJyb3IKICAgIEVYSVQgL2IgMQoKOmluZm8KICAgIEVDSE8uSU5GTzogJSoKICAgIEVYSVQgL2IgMAoKOmVvZkVycm9yCkVYSVQgL2IgMQpHT1RPIDplb2YKCjplb2ZTdWNjZXNzCkVYSVQgL2IgMAo`
+	powershellScript = `IyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjCiMjICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAjIwojIyAgbWFnZXBsdXMgYm9vdHN0cmFwIHdyYXBwZXIgZm9yIFBvd2VyU2hlbGwgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIyMKIyMgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICMjCiMjICBUaGUgY29uZmlndXJhdGlvbiBpcyBsb2NhdGVkIGluIC5tYWdlcGx1cy93cmFwcGVyLnByb3BlcnRpZXMgICAgICAgICAgICAjIwojIyAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgIyMKIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjCiMjICBETyBOT1QgRURJVCEhICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAgICAjIwojIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMjIyMKJEVycm9yQWN0aW9uUHJlZmVyZW5jZSA9ICdTdG9wJwokUHJvZ3Jlc3NQcmVmZXJlbmNlID0gJ1NpbGVudGx5Q29udGludWUnCgpmdW5jdGlvbiBGYXRhbChbc3RyaW5nXSAkbWVzc2FnZSkgewogICAgW0NvbnNvbGVdOjpFcnJvci5Xcml0ZUxpbmUoIkZBVEFMOiAkbWVzc2FnZSIpCiAgICBleGl0IDEKfQoKZnVuY3Rpb24gSW5mbyhbc3RyaW5nXSAkbWVzc2FnZSkgewogICAgW0NvbnNvbGVdOjpFcnJvci5Xcml0ZUxpbmUoIklORk86ICRtZXNzYWdlIikKfQoKZnVuY3Rpb24gSW52b2tlLURvd25sb2FkKFtzdHJpbmddICR1cmwsIFtzdHJpbmddICR0YXJnZXQpIHsKICAgICRoZWFkZXJzID0gQHt9CiAgICBpZiAoJGVudjpNQUdFUExVU1dfVE9LRU4pIHsKICAgICAgICAkaGVhZGVyc1snQXV0aG9yaXphdGlvbiddID0gIkJlYXJlciAkKCRlbnY6TUFHRVBMVVNXX1RPS0VOKSIKICAgIH0gZWxzZWlmICgkZW52Ok1BR0VQTFVTV19VU0VSTkFNRSkgewogICAgICAgICRjcmVkZW50aWFscyA9ICIkKCRlbnY6TUFHRVBMVVNXX1VTRVJOQU1FKTokKCRlbnY6TUFHRVBMVVNXX1BBU1NXT1JEKSIKICAgICAgICAkaGVhZGVyc1snQXV0aG9yaXphdGlvbiddID0gJ0Jhc2ljICcgKyBbQ29udmVydF06OlRvQmFzZTY0U3RyaW5nKFtUZXh0LkVuY29kaW5nXTo6VVRGOC5HZXRCeXRlcygkY3JlZGVudGlhbHMpKQogICAgfQogICAgSW52b2tlLVdlYlJlcXVlc3QgLVVyaSAkdXJsIC1PdXRGaWxlICR0YXJnZXQgLUhlYWRlcnMgJGhlYWRlcnMgLVVzZUJhc2ljUGFyc2luZwp9CgpmdW5jdGlvbiBJbnN0YWxsLUJpbmFyeSB7CiAgICAkZXhwZWN0ZWRDaGVja3N1bSA9ICRwcm9wZXJ0aWVzWyJjaGVja3N1bS4kb3MtJGFyY2giXQogICAgaWYgKC1ub3QgJGV4cGVjdGVkQ2hlY2tzdW0pIHsKICAgICAgICBGYXRhbCAiVGhlcmUgaXMgbm8gY2hlY2tzdW0gZm9yICRvcy0kYXJjaCBjb25maWd1cmVkIGluICRwcm9wZXJ0aWVzRmlsZS4gVHJ5IGRvd25sb2FkIG1hZ2VwbHVzIGJpbmFyeSBhbmQgcnVuOiBtYWdlcGx1cyAtd3JhcHBlciIKICAgIH0KCiAgICAkYmluYXJ5RG93bmxvYWRVcmwgPSAiJHJlbGVhc2VzVXJsL2Rvd25sb2FkL3YkdmVyc2lvbi9tYWdlcGx1c18kKCR2ZXJzaW9uKV8kb3MtJGFyY2gkZG93bmxvYWRFeHQiCiAgICAkdG1wRGlyZWN0b3J5ID0gIiRiaW5hcnkuJChHZXQtUmFuZG9tKS50bXAiCiAgICAkdG1wQXJjaGl2ZSA9IEpvaW4tUGF0aCAkdG1wRGlyZWN0b3J5ICJ0bXAkZG93bmxvYWRFeHQiCgogICAgdHJ5IHsKICAgICAgICBOZXctSXRlbSAtUGF0aCAkdG1wRGlyZWN0b3J5IC1JdGVtVHlwZSBEaXJlY3RvcnkgLUZvcmNlIHwgT3V0LU51bGwKICAgIH0gY2F0Y2ggewogICAgICAgIEZhdGFsICJDYW5ub3QgY3JlYXRlIGNhY2hlIGRpcmVjdG9yeSBmb3Igc3RvcmluZyBiaW5hcmllczogJF8iCiAgICB9CgogICAgdHJ5IHsKICAgICAgICBpZiAoJGVudjpNQUdFUExVU1dfQVJDSElWRSkgewogICAgICAgICAgICAkYmluYXJ5RG93bmxvYWRVcmwgPSAkZW52Ok1BR0VQTFVTV19BUkNISVZFCiAgICAgICAgICAgIEluZm8gIkluc3RhbGxpbmcgJGJpbmFyeURvd25sb2FkVXJsLi4uIgogICAgICAgICAgICBDb3B5LUl0ZW0gLVBhdGggJGVudjpNQUdFUExVU1dfQVJDSElWRSAtRGVzdGluYXRpb24gJHRtcEFyY2hpdmUKICAgICAgICB9IGVsc2UgewogICAgICAgICAgICBJbmZvICJEb3dubG9hZGluZyAkYmluYXJ5RG93bmxvYWRVcmwuLi4iCiAgICAgICAgICAgIEludm9rZS1Eb3dubG9hZCAkYmluYXJ5RG93bmxvYWRVcmwgJHRtcEFyY2hpdmUKICAgICAgICB9CiAgICB9IGNhdGNoIHsKICAgICAgICBSZW1vdmUtSXRlbSAtUGF0aCAkdG1wRGlyZWN0b3J5IC1SZWN1cnNlIC1Gb3JjZSAtRXJyb3JBY3Rpb24gU2lsZW50bHlDb250aW51ZQogICAgICAgIEZhdGFsICJXYXMgbm90IGFibGUgdG8gZG93bmxvYWQgYmluYXJ5IGZyb20gJCgkYmluYXJ5RG93bmxvYWRVcmwpOiAkXyIKICAgIH0KCiAgICAkYWN0dWFsQ2hlY2tzdW0gPSAoR2V0LUZpbGVIYXNoIC1BbGdvcml0aG0gU0hBMjU2IC1QYXRoICR0bXBBcmNoaXZlKS5IYXNoLlRvTG93ZXIoKQogICAgaWYgKCRhY3R1YWxDaGVja3N1bSAtbmUgJGV4cGVjdGVkQ2hlY2tzdW0uVG9Mb3dlcigpKSB7CiAgICAgICAgUmVtb3ZlLUl0ZW0gLVBhdGggJHRtcERpcmVjdG9yeSAtUmVjdXJzZSAtRm9yY2UgLUVycm9yQWN0aW9uIFNpbGVudGx5Q29udGludWUKICAgICAgICBGYXRhbCAiQ2hlY2tzdW0gbWlzbWF0Y2ggb2YgJCgkYmluYXJ5RG93bmxvYWRVcmwpOiBleHBlY3RlZCAkZXhwZWN0ZWRDaGVja3N1bSBidXQgZ290ICRhY3R1YWxDaGVja3N1bS4iCiAgICB9CgogICAgdHJ5IHsKICAgICAgICBpZiAoJGRvd25sb2FkRXh0IC1lcSAnLnppcCcpIHsKICAgICAgICAgICAgRXhwYW5kLUFyY2hpdmUgLVBhdGggJHRtcEFyY2hpdmUgLURlc3RpbmF0aW9uUGF0aCAkdG1wRGlyZWN0b3J5IC1Gb3JjZQogICAgICAgIH0gZWxzZSB7CiAgICAgICAgICAgIHRhciAteHpmICR0bXBBcmNoaXZlIC1DICR0bXBEaXJlY3RvcnkKICAgICAgICAgICAgaWYgKCRMQVNURVhJVENPREUgLW5lIDApIHsKICAgICAgICAgICAgICAgIHRocm93ICJ0YXIgZXhpdGVkIHdpdGggJExBU1RFWElUQ09ERSIKICAgICAgICAgICAgfQogICAgICAgIH0KICAgICAgICBNb3ZlLUl0ZW0gLVBhdGggKEpvaW4tUGF0aCAkdG1wRGlyZWN0b3J5ICJtYWdlcGx1cyRleHQiKSAtRGVzdGluYXRpb24gJGJpbmFyeSAtRm9yY2UKICAgICAgICBpZiAoJG9zIC1uZSAnV2luZG93cycpIHsKICAgICAgICAgICAgY2htb2QgK3ggJGJpbmFyeQogICAgICAgIH0KICAgIH0gY2F0Y2ggewogICAgICAgIEZhdGFsICJXYXMgbm90IGFibGUgdG8gZXh0cmFjdCAkKCR0bXBBcmNoaXZlKTogJF8iCiAgICB9IGZpbmFsbHkgewogICAgICAgIFJlbW92ZS1JdGVtIC1QYXRoICR0bXBEaXJlY3RvcnkgLVJlY3Vyc2UgLUZvcmNlIC1FcnJvckFjdGlvbiBTaWxlbnRseUNvbnRpbnVlCiAgICB9Cn0KCiRwcm9wZXJ0aWVzRmlsZSA9IEpvaW4tUGF0aCAkUFNTY3JpcHRSb290ICcubWFnZXBsdXMvd3JhcHBlci5wcm9wZXJ0aWVzJwppZiAoLW5vdCAoVGVzdC1QYXRoIC1QYXRoICRwcm9wZXJ0aWVzRmlsZSAtUGF0aFR5cGUgTGVhZikpIHsKICAgIEZhdGFsICJUaGlzIG1hZ2VwbHVzIHdyYXBwZXIgd2FzIG5vdCBpbml0aWF0ZWQgY29ycmVjdGx5OiAkcHJvcGVydGllc0ZpbGUgaXMgbWlzc2luZy4gVHJ5IGRvd25sb2FkIG1hZ2VwbHVzIGJpbmFyeSBhbmQgcnVuOiBtYWdlcGx1cyAtd3JhcHBlciIKfQokcHJvcGVydGllcyA9IEB7fQpmb3JlYWNoICgkbGluZSBpbiBHZXQtQ29udGVudCAtUGF0aCAkcHJvcGVydGllc0ZpbGUpIHsKICAgICRsaW5lID0gJGxpbmUuVHJpbSgpCiAgICBpZiAoJGxpbmUgLWVxICcnIC1vciAkbGluZS5TdGFydHNXaXRoKCcjJykpIHsKICAgICAgICBjb250aW51ZQogICAgfQogICAgJHBhcnRzID0gJGxpbmUuU3BsaXQoJz0nLCAyKQogICAgaWYgKCRwYXJ0cy5Db3VudCAtZXEgMikgewogICAgICAgICRwcm9wZXJ0aWVzWyRwYXJ0c1swXS5UcmltKCldID0gJHBhcnRzWzFdLlRyaW0oKQogICAgfQp9CgokdmVyc2lvbiA9ICRwcm9wZXJ0aWVzWyd2ZXJzaW9uJ10KaWYgKC1ub3QgJHZlcnNpb24pIHsKICAgIEZhdGFsICJUaGVyZSBpcyBubyB2ZXJzaW9uIGNvbmZpZ3VyZWQgaW4gJHByb3BlcnRpZXNGaWxlLiIKfQokZ29WZXJzaW9uID0gJHByb3BlcnRpZXNbJ2dvVmVyc2lvbiddCiRyZWxlYXNlc1VybCA9ICRwcm9wZXJ0aWVzWydyZWxlYXNlc1VybCddCmlmICgkZW52Ok1BR0VQTFVTV19CQVNFX1VSTCkgewogICAgJHJlbGVhc2VzVXJsID0gJGVudjpNQUdFUExVU1dfQkFTRV9VUkwKfSBlbHNlaWYgKC1ub3QgJHJlbGVhc2VzVXJsKSB7CiAgICAkcmVsZWFzZXNVcmwgPSAnaHR0cHM6Ly9naXRodWIuY29tL2VjaG9jYXQvbWFnZXBsdXMvcmVsZWFzZXMnCn0KCmlmICgkSXNMaW51eCkgewogICAgJG9zID0gJ0xpbnV4Jwp9IGVsc2VpZiAoJElzTWFjT1MpIHsKICAgICRvcyA9ICdtYWNPUycKfSBlbHNlIHsKICAgICRvcyA9ICdXaW5kb3dzJwp9CgppZiAoJGVudjpQUk9DRVNTT1JfQVJDSElURVc2NDMyKSB7CiAgICAkcGxhaW5BcmNoID0gJGVudjpQUk9DRVNTT1JfQVJDSElURVc2NDMyCn0gZWxzZWlmICgkZW52OlBST0NFU1NPUl9BUkNISVRFQ1RVUkUpIHsKICAgICRwbGFpbkFyY2ggPSAkZW52OlBST0NFU1NPUl9BUkNISVRFQ1RVUkUKfSBlbHNlIHsKICAgICRwbGFpbkFyY2ggPSB1bmFtZSAtbQp9CnN3aXRjaCAtV2lsZGNhcmQgKCRwbGFpbkFyY2gpIHsKICAgICdBTUQ2NCcgICB7ICRhcmNoID0gJzY0Yml0JyB9CiAgICAneDg2XzY0KicgeyAkYXJjaCA9ICc2NGJpdCcgfQogICAgJ3g4NicgICAgIHsgJGFyY2ggPSAnMzJiaXQnIH0KICAgICdpMzg2KicgICB7ICRhcmNoID0gJzMyYml0JyB9CiAgICAnQVJNNjQnICAgeyAkYXJjaCA9ICdBUk02NCcgfQogICAgJ2FybTY0KicgIHsgJGFyY2ggPSAnQVJNNjQnIH0KICAgICdhYXJjaDY0JyB7ICRhcmNoID0gJ0FSTTY0JyB9CiAgICAnYXJtKicgICAgeyBpZiAoLW5vdCAkYXJjaCkgeyAkYXJjaCA9ICdBUk0nIH0gfQogICAgZGVmYXVsdCAgIHsgRmF0YWwgIlVuc3VwcG9ydGVkIGFyY2hpdGVjdHVyZTogJHBsYWluQXJjaCIgfQp9CgppZiAoJG9zIC1lcSAnV2luZG93cycpIHsKICAgICRleHQgPSAnLmV4ZScKICAgICRkb3dubG9hZEV4dCA9ICcuemlwJwp9IGVsc2UgewogICAgJGV4dCA9ICcnCiAgICAkZG93bmxvYWRFeHQgPSAnLnRhci5neicKfQoKJGJpbmFyaWVzQ2FjaGVEaXIgPSAkcHJvcGVydGllc1snY2FjaGVEaXInXQppZiAoLW5vdCAkYmluYXJpZXNDYWNoZURpcikgewogICAgaWYgKCRvcyAtZXEgJ1dpbmRvd3MnKSB7CiAgICAgICAgJGJpbmFyaWVzQ2FjaGVEaXIgPSBKb2luLVBhdGggJGVudjpMT0NBTEFQUERBVEEgJ21hZ2VwbHVzXGJpbmFyaWVzJwogICAgfSBlbHNlIHsKICAgICAgICAkYmluYXJpZXNDYWNoZURpciA9IEpvaW4tUGF0aCAkSE9NRSAnLm1hZ2VwbHVzL2JpbmFyaWVzJwogICAgfQp9CiRiaW5hcnkgPSBKb2luLVBhdGggJGJpbmFyaWVzQ2FjaGVEaXIgIm1hZ2VwbHVzLSRvcy0kYXJjaC0kdmVyc2lvbiRleHQiCgppZiAoJGVudjpNQUdFUExVU1dfSUdOT1JFX0RPQ0tFUl9JTUFHRV9NSVNNQVRDSCAtbmUgJ3llcycpIHsKICAgIGlmIChUZXN0LVBhdGggLVBhdGggJy91c3IvbGliL21hZ2VwbHVzL2RvY2tlci12ZXJzaW9uJyAtUGF0aFR5cGUgTGVhZikgewogICAgICAgICRkb2NrZXJWZXJzaW9uID0gKEdldC1Db250ZW50IC1QYXRoICcvdXNyL2xpYi9tYWdlcGx1cy9kb2NrZXItdmVyc2lvbicgLVJhdykuVHJpbSgpCiAgICAgICAgaWYgKCRkb2NrZXJWZXJzaW9uIC1uZSAkdmVyc2lvbikgewogICAgICAgICAgICBpZiAoVGVzdC1QYXRoIC1QYXRoICcvdXNyL2xpYi9tYWdlcGx1cy9kb2NrZXItaW1hZ2UnIC1QYXRoVHlwZSBMZWFmKSB7CiAgICAgICAgICAgICAgICAkZG9ja2VySW1hZ2UgPSAoR2V0LUNvbnRlbnQgLVBhdGggJy91c3IvbGliL21hZ2VwbHVzL2RvY2tlci1pbWFnZScgLVJhdykuVHJpbSgpCiAgICAgICAgICAgIH0gZWxzZSB7CiAgICAgICAgICAgICAgICAkZG9ja2VySW1hZ2UgPSAnZWNob2NhdC9tYWdlcGx1cycKICAgICAgICAgICAgfQogICAgICAgICAgICBGYXRhbCAoIllvdSdyZSBhcmUgdXNpbmcgbWFnZXBsdXN3IHdpdGggdmVyc2lvbiAkdmVyc2lvbiBpbnNpZGUgb2YgYSBtYWdlcGx1cyBkb2NrZXIgaW1hZ2Ugd2l0aCB2ZXJzaW9uICRkb2NrZXJWZXJzaW9uLiIgKwogICAgICAgICAgICAgICAgIiBUaGlzIGNvdWxkIGxlYWQgdG8gdW5leHBlY3RlZCBiZWhhdmlvcnMuIFdlIHJlY29tbWVuZCB0byBhbGlnbiBib3RoIHZlcnNpb25zIHRvZ2V0aGVyIGJ5IGVpdGhlcjoiICsKICAgICAgICAgICAgICAgICJgbmB0MS4pIENoYW5nZSAkcHJvcGVydGllc0ZpbGUgdG86IHZlcnNpb249JGRvY2tlclZlcnNpb24iICsKICAgICAgICAgICAgICAgICJgbmB0Mi4pIC4uLiBvciBzZXQgdGhlIHVzZWQgaW1hZ2UgdG86ICQoJGRvY2tlckltYWdlKTokdmVyc2lvbiIgKwogICAgICAgICAgICAgICAgImBuWW91IGNhbiBzdXBwcmVzcyB0aGlzIGVycm9yIGJ5IHNldCBNQUdFUExVU1dfSUdOT1JFX0RPQ0tFUl9JTUFHRV9NSVNNQVRDSD15ZXMiKQogICAgICAgIH0KICAgIH0KfQoKJHVwVG9EYXRlID0gJGZhbHNlCmlmIChUZXN0LVBhdGggLVBhdGggJGJpbmFyeSAtUGF0aFR5cGUgTGVhZikgewogICAgJHVwVG9EYXRlID0gW2Jvb2xdKCYgJGJpbmFyeSAtdmVyc2lvbiAyPiYxIHwgU2VsZWN0LVN0cmluZyAtU2ltcGxlTWF0Y2ggJHZlcnNpb24pCn0KaWYgKC1ub3QgJHVwVG9EYXRlKSB7CiAgICBJbnN0YWxsLUJpbmFyeQp9CgppZiAoJGdvVmVyc2lvbikgewogICAgJiAkYmluYXJ5IC1nbyAkZ29WZXJzaW9uIEBhcmdzCn0gZWxzZSB7CiAgICAmICRiaW5hcnkgQGFyZ3MKfQpleGl0ICRMQVNURVhJVENPREUK`
 }
@@ -1,5 +0,0 @@
-//+build !windows
-
-package wrapper
-
-func noticeAfterCreation(unixScriptFile string) {}
@@ -2,59 +2,107 @@ package wrapper
 
 import (
 	"encoding/base64"
+	"fmt"
+	mio "github.com/echocat/mageplus/io"
+	"github.com/echocat/mageplus/release"
 	"os"
 	"path/filepath"
 	"strings"
 )
 
 var (
-	unixScript    = ``
-	windowsScript = ``
+	unixScript       = ``
+	windowsScript    = ``
+	powershellScript = ``
 )
 
+// Options controls how the wrapper is written by WriteWith.
+type Options struct {
+	Version        string    // Version of mageplus the wrapper should use
+	GoVersion      string    // Version of the golang SDK to pin; if empty the existing one is kept
+	ReleasesUrl    string    // Base URL of the releases; if empty the existing or default one is used
+	Flavours       []Flavour // Flavours of the scripts to write; if empty AllFlavours are written
+	FetchChecksums bool      // If true missing checksums of the binaries are retrieved from the release, which requires network access
+}
+
 func Write(targetDir string, version string) error {
-	//noinspection GoBoolExpressions
-	if unixScript == "" || windowsScript == "" {
-		panic("unixScript and/or windowsScript are still empty. resources.go not generated before building?")
-	}
-	unixScriptFile := filepath.Join(targetDir, "mageplusw")
-	windowsScriptFile := filepath.Join(targetDir, "mageplusw.cmd")
-	if unixScriptFileExists, err := exists(unixScriptFile); err != nil {
+	return WriteWith(targetDir, Options{Version: version})
+}
+
+func WriteWith(targetDir string, options Options) error {
+	ensureScripts()
+	if properties, err := propertiesFor(targetDir, options); err != nil {
 		return err
-	} else if err := writeFile(unixScriptFile, unixScript, version, false, 0755); err != nil {
+	} else if err := ensureChecksums(&properties, options); err != nil {
 		return err
-	} else if err := writeFile(windowsScriptFile, windowsScript, version, true, 0644); err != nil {
+	} else if err := properties.Write(filepath.Join(targetDir, PropertiesFile)); err != nil {
 		return err
 	} else {
-		if unixScriptFileExists {
-			noticeAfterCreation(unixScriptFile)
+		flavours := flavoursOf(options)
+		for _, flavour := range flavours {
+			raw, crlf, perm := flavour.script()
+			if err := writeFile(filepath.Join(targetDir, flavour.FileName()), raw, crlf, perm); err != nil {
+				return err
+			}
 		}
+		return prepareGit(targetDir, flavours)
+	}
+}
+
+func ensureScripts() {
+	//noinspection GoBoolExpressions
+	if unixScript == "" || windowsScript == "" || powershellScript == "" {
+		panic("unixScript, windowsScript and/or powershellScript are still empty. resources.go not generated before building?")
+	}
+}
+
+// ensureChecksums retrieves the checksums of all platforms of the configured
+// release if not already present and Options.FetchChecksums is set, so the
+// wrapper scripts are able to verify the downloaded binaries. Without them the
+// scripts refuse to download anything. An explicit Options.ReleasesUrl takes
+// precedence over the environment (see release.BaseUrl).
+func ensureChecksums(properties *Properties, options Options) error {
+	if len(properties.Checksums) > 0 || !options.FetchChecksums {
 		return nil
 	}
+	baseUrl := release.BaseUrl(properties.ReleasesUrl)
+	if options.ReleasesUrl != "" {
+		baseUrl = properties.ReleasesUrl
+	}
+	r, err := release.Get(baseUrl, properties.Version)
+	if err != nil {
+		return fmt.Errorf("cannot retrieve checksums for the wrapper: %v", err)
+	}
+	properties.Checksums = r.ChecksumsByPlatform()
+	if len(properties.Checksums) == 0 {
+		return fmt.Errorf("cannot retrieve checksums for the wrapper: release %s does not contain any checksums of its binaries", properties.Version)
+	}
+	return nil
 }
 
-func writeFile(target string, rawBase64EncodedContent string, version string, crlf bool, perm os.FileMode) error {
-	if content, err := prepareContent(rawBase64EncodedContent, version, crlf); err != nil {
+func writeFile(target string, rawBase64EncodedContent string, crlf bool, perm os.FileMode) error {
+	if content, err := prepareContent(rawBase64EncodedContent, crlf); err != nil {
 		return err
 	} else if err := createDirectorsForFileIfRequired(target); err != nil {
 		return err
-	} else if f, err := openFile(target, perm); err != nil {
+	} else if err := mio.WriteFileAtomic(target, content, perm); err != nil {
 		return err
-	} else {
-		defer f.Close()
-		_, err := f.Write(content)
+	} else if fi, err := os.Stat(target); err != nil {
 		return err
+	} else if executable := perm & 0111; fi.Mode().Perm()&executable != executable {
+		// Existing scripts keep their permissions but still need to be
+		// executable.
+		return os.Chmod(target, fi.Mode().Perm()|executable)
+	} else {
+		return nil
 	}
 }
 
-func prepareContent(rawBase64EncodedContent string, version string, crlf bool) ([]byte, error) {
+func prepareContent(rawBase64EncodedContent string, crlf bool) ([]byte, error) {
 	if b, err := base64.RawURLEncoding.DecodeString(rawBase64EncodedContent); err != nil {
 		return nil, err
 	} else {
-		if strings.HasPrefix(version, "v") {
-			version = version[1:]
-		}
-		content := strings.Replace(string(b), "####VERSION####", version, -1)
+		content := string(b)
 		if crlf {
 			content = strings.ReplaceAll(content, "\n", "\r\n")
 		}
@@ -66,10 +114,6 @@ func createDirectorsForFileIfRequired(file string) error {
 	return os.MkdirAll(filepath.Dir(file), 0755)
 }
 
-func openFile(file string, perm os.FileMode) (*os.File, error) {
-	return os.OpenFile(file, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, perm)
-}
-
 func exists(file string) (bool, error) {
 	if _, err := os.Stat(file); os.IsNotExist(err) {
 		return false, nil